@@ -33,12 +33,14 @@ import (
 	utilwait "k8s.io/apimachinery/pkg/util/wait"
 
 	"gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/logging"
+	logmetrics "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/logging/metrics"
 	"gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/multus"
 	srv "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/server"
 	"gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/server/api"
 	"gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/server/config"
 	"gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/types"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
@@ -59,26 +61,28 @@ func main() {
 
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
+	defer logging.Close()
 
 	daemonConf, err := cniServerConfig(*configFilePath)
 	if err != nil {
-		os.Exit(1)
+		exit(1)
 	}
 
 	multusConf, err := config.ParseMultusConfig(*configFilePath)
 	if err != nil {
 		logging.Panicf("startMultusDaemon failed to load the multus configuration: %v", err)
-		os.Exit(1)
+		exit(1)
 	}
 
 	logging.Verbosef("multus-daemon started")
+	logging.LogStartupInfo(multus.PrintVersionString())
 
 	if multusConf.ReadinessIndicatorFile != "" {
 		// Check readinessindicator file before daemon launch
 		logging.Verbosef("Readiness Indicator file check")
 		if err := types.GetReadinessIndicatorFile(multusConf.ReadinessIndicatorFile); err != nil {
 			_ = logging.Errorf("have you checked that your default network is ready? still waiting for readinessindicatorfile @ %v. pollimmediate error: %v", multusConf.ReadinessIndicatorFile, err)
-			os.Exit(1)
+			exit(1)
 		}
 		logging.Verbosef("Readiness Indicator file check done!")
 	}
@@ -94,7 +98,7 @@ func main() {
 		configManager, err = config.NewManager(*multusConf)
 		if err != nil {
 			_ = logging.Errorf("failed to create the configuration manager for the primary CNI plugin: %v", err)
-			os.Exit(2)
+			exit(2)
 		}
 		// ConfigManager watches the readiness indicator file (if configured)
 		// and exits the daemon when that is removed. The CNIServer does
@@ -108,14 +112,14 @@ func main() {
 
 	if err := startMultusDaemon(ctx, daemonConf, ignoreReadinessIndicator); err != nil {
 		logging.Panicf("failed start the multus thick-plugin listener: %v", err)
-		os.Exit(3)
+		exit(3)
 	}
 
 	// Wait until daemon ready
 	logging.Verbosef("API readiness check")
 	if waitUntilAPIReady(daemonConf.SocketDir) != nil {
 		logging.Panicf("failed to ready multus-daemon socket: %v", err)
-		os.Exit(1)
+		exit(1)
 	}
 	logging.Verbosef("API readiness check done!")
 
@@ -132,7 +136,7 @@ func main() {
 	if configManager != nil {
 		if err := configManager.Start(ctx, &wg); err != nil {
 			_ = logging.Errorf("failed to start config manager: %v", err)
-			os.Exit(3)
+			exit(3)
 		}
 	}
 
@@ -140,6 +144,13 @@ func main() {
 	logging.Verbosef("multus daemon is exited")
 }
 
+// exit closes the log, which the deferred logging.Close does not on os.Exit,
+// and exits with code
+func exit(code int) {
+	_ = logging.Close()
+	os.Exit(code)
+}
+
 func waitUntilAPIReady(socketPath string) error {
 	apiReadyPollDuration := 100 * time.Millisecond
 	apiReadyPollTimeout := 1000 * time.Millisecond
@@ -165,6 +176,7 @@ func startMultusDaemon(ctx context.Context, daemonConfig *srv.ControllerNetConf,
 	}
 
 	if daemonConfig.MetricsPort != nil {
+		prometheus.MustRegister(logmetrics.Collector())
 		go utilwait.UntilWithContext(ctx, func(ctx context.Context) {
 			http.Handle("/metrics", promhttp.Handler())
 			logging.Debugf("metrics port: %d", *daemonConfig.MetricsPort)
@@ -0,0 +1,171 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+const defaultAsyncBufferSize = 1024
+
+// asyncDropped counts the log lines dropped because the async queue was full
+var asyncDropped atomic.Uint64
+
+// asyncLine is a log line queued for the async writer, or a flush request
+// when flushed is set
+type asyncLine struct {
+	level   Level
+	line    []byte
+	dest    destinations
+	flushed chan struct{}
+}
+
+// asyncWriter writes the log lines from a bounded queue in a background
+// goroutine
+type asyncWriter struct {
+	lines chan asyncLine
+	done  chan struct{}
+}
+
+func newAsyncWriter(bufferSize int) *asyncWriter {
+	a := &asyncWriter{
+		lines: make(chan asyncLine, bufferSize),
+		done:  make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+func (a *asyncWriter) run() {
+	defer close(a.done)
+	for l := range a.lines {
+		if l.flushed != nil {
+			close(l.flushed)
+			continue
+		}
+		l.dest.write(l.level, l.line)
+	}
+}
+
+// enqueue queues a copy of the line for writing to d, or drops it when the
+// queue is full
+func (a *asyncWriter) enqueue(level Level, line []byte, d destinations) {
+	select {
+	case a.lines <- asyncLine{level: level, line: append([]byte(nil), line...), dest: d}:
+	default:
+		asyncDropped.Add(1)
+	}
+}
+
+// flush waits until the lines queued so far are written
+func (a *asyncWriter) flush() {
+	flushed := make(chan struct{})
+	a.lines <- asyncLine{flushed: flushed}
+	<-flushed
+}
+
+// drain waits until the lines queued so far are written, or until ctx is
+// done. Then it drops the lines still queued and returns how many
+func (a *asyncWriter) drain(ctx context.Context) (int, error) {
+	flushed := make(chan struct{})
+	select {
+	case a.lines <- asyncLine{flushed: flushed}:
+		select {
+		case <-flushed:
+			return 0, nil
+		case <-ctx.Done():
+		}
+	case <-ctx.Done():
+	}
+
+	dropped := 0
+	for {
+		select {
+		case l := <-a.lines:
+			if l.flushed != nil {
+				close(l.flushed)
+				continue
+			}
+			dropped++
+		default:
+			asyncDropped.Add(uint64(dropped))
+			return dropped, ctx.Err()
+		}
+	}
+}
+
+// close writes the queued lines and stops the background goroutine
+func (a *asyncWriter) close() {
+	close(a.lines)
+	<-a.done
+}
+
+// EnableAsync writes log lines from a queue of bufferSize lines in a
+// background goroutine, so that slow destinations do not block the callers.
+// Lines logged while the queue is full are dropped, and counted by
+// AsyncDropped. Error, panic and fatal lines are written synchronously, after
+// the queued lines, so that they are not lost on a crash. Zero or less uses a
+// default size. Flush writes the queued lines, and Close stops the goroutine
+func EnableAsync(bufferSize int) {
+	if bufferSize <= 0 {
+		bufferSize = defaultAsyncBufferSize
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	if loggingAsync != nil {
+		loggingAsync.close()
+	}
+	loggingAsync = newAsyncWriter(bufferSize)
+}
+
+// AsyncDropped returns the number of log lines dropped because the async
+// queue was full
+func AsyncDropped() uint64 {
+	return asyncDropped.Load()
+}
+
+// AsyncDrainError is returned by DrainWithContext when the context is done
+// before the queued log lines are written
+type AsyncDrainError struct {
+	// Dropped is the number of queued log lines dropped
+	Dropped int
+	Err     error
+}
+
+func (e *AsyncDrainError) Error() string {
+	return fmt.Sprintf("dropped %d queued log lines: %v", e.Dropped, e.Err)
+}
+
+func (e *AsyncDrainError) Unwrap() error {
+	return e.Err
+}
+
+// DrainWithContext waits until the log lines queued by the async writer are
+// written, as Flush does, or until ctx is done, e.g. on shutdown. Then it
+// drops the lines still queued, counted by AsyncDropped, and returns an
+// *AsyncDrainError with their number
+func DrainWithContext(ctx context.Context) error {
+	loggingMutex.RLock()
+	defer loggingMutex.RUnlock()
+	if loggingAsync == nil {
+		return nil
+	}
+	if dropped, err := loggingAsync.drain(ctx); err != nil {
+		return &AsyncDrainError{Dropped: dropped, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+// blockingWriter records Writes, each blocked until release is closed
+type blockingWriter struct {
+	mu      sync.Mutex
+	writes  []string
+	started chan struct{}
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.started <- struct{}{}
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+var _ = Describe("logging async writer", func() {
+	var w *writeRecorder
+
+	BeforeEach(func() {
+		w = recordLogs(DebugLevel)
+	})
+
+	AfterEach(func() {
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	It("Check lines are written by the background goroutine", func() {
+		EnableAsync(10)
+		Verbosef("first")
+		Debugf("second")
+		gomega.Expect(Flush()).To(gomega.Succeed())
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[verbose] first\n", "[debug] second\n"}))
+
+		Verbosef("third")
+		gomega.Expect(Close()).To(gomega.Succeed())
+		gomega.Expect(loggingAsync).To(gomega.BeNil())
+		gomega.Expect(w.writes).To(gomega.HaveLen(3))
+	})
+
+	It("Check errors are written synchronously after the queued lines", func() {
+		EnableAsync(10)
+		Verbosef("queued")
+		_ = Errorf("failed")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[verbose] queued\n", "[error] failed\n"}))
+	})
+
+	It("Check lines are dropped when the queue is full", func() {
+		bw := &blockingWriter{started: make(chan struct{}, 1), release: make(chan struct{})}
+		loggingW = bw
+		EnableAsync(1)
+
+		Verbosef("written")
+		gomega.Eventually(bw.started).Should(gomega.Receive())
+		Verbosef("queued")
+		Verbosef("dropped")
+		gomega.Expect(AsyncDropped()).To(gomega.Equal(uint64(1)))
+
+		close(bw.release)
+		gomega.Expect(Flush()).To(gomega.Succeed())
+		gomega.Expect(bw.writes).To(gomega.Equal([]string{"[verbose] written\n", "[verbose] queued\n"}))
+	})
+
+	It("Check Flush and Close without the async writer", func() {
+		gomega.Expect(Flush()).To(gomega.Succeed())
+		Verbosef("sync")
+		gomega.Expect(Flush()).To(gomega.Succeed())
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[verbose] sync\n"}))
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	It("Check DrainWithContext writes the queued lines", func() {
+		gomega.Expect(DrainWithContext(context.Background())).To(gomega.Succeed())
+		EnableAsync(10)
+		Verbosef("first")
+		Debugf("second")
+		gomega.Expect(DrainWithContext(context.Background())).To(gomega.Succeed())
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[verbose] first\n", "[debug] second\n"}))
+	})
+
+	It("Check DrainWithContext drops the queued lines when the context is done", func() {
+		bw := &blockingWriter{started: make(chan struct{}, 1), release: make(chan struct{})}
+		loggingW = bw
+		EnableAsync(10)
+
+		Verbosef("written")
+		gomega.Eventually(bw.started).Should(gomega.Receive())
+		Verbosef("dropped")
+		Verbosef("dropped")
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err := DrainWithContext(ctx)
+		var drainErr *AsyncDrainError
+		gomega.Expect(errors.As(err, &drainErr)).To(gomega.BeTrue())
+		gomega.Expect(drainErr.Dropped).To(gomega.Equal(2))
+		gomega.Expect(errors.Is(err, context.DeadlineExceeded)).To(gomega.BeTrue())
+		gomega.Expect(AsyncDropped()).To(gomega.Equal(uint64(2)))
+
+		close(bw.release)
+		gomega.Expect(Flush()).To(gomega.Succeed())
+		gomega.Expect(bw.writes).To(gomega.Equal([]string{"[verbose] written\n"}))
+	})
+
+})
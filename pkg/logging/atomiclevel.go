@@ -0,0 +1,45 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import "sync/atomic"
+
+// atomicLevel is a Level read without loggingMutex, so that the logging
+// calls below the logging level do not contend on it. It is written with
+// loggingMutex held, as the rest of the logging state
+type atomicLevel struct {
+	v atomic.Uint32
+}
+
+// Load returns the level
+func (a *atomicLevel) Load() Level {
+	return Level(a.v.Load())
+}
+
+// Store sets the level
+func (a *atomicLevel) Store(level Level) {
+	a.v.Store(uint32(level))
+}
+
+// mayLog reports whether a message of given level from e may be logged,
+// without loggingMutex: it passes the logging level, or the highest level
+// of the components for a named entry. The logging calls check it before
+// taking loggingMutex, and logEnabled then tells whether it is logged
+func mayLog(e *LogEntry, level Level) bool {
+	if levelEnabled(level, loggingLevel.Load()) {
+		return true
+	}
+	return e != nil && e.component != "" && levelEnabled(level, loggingComponentMaxLevel.Load())
+}
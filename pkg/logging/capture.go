@@ -0,0 +1,117 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"io"
+	"strings"
+	"sync"
+)
+
+// capturedLine is a log line kept by a Capture
+type capturedLine struct {
+	level Level
+	line  string
+}
+
+// Capture keeps the log lines in memory in place of the log destinations,
+// so that tests can assert what was logged
+type Capture struct {
+	mu    sync.Mutex
+	lines []capturedLine
+
+	// the log destinations and the capture replaced by this one
+	stderr   bool
+	stdout   bool
+	w        io.Writer
+	outputs  []io.Writer
+	previous *Capture
+}
+
+// CaptureLogs writes the log lines to a new Capture in place of stderr,
+// stdout, the log file and the writers added by AddOutput, until the
+// Capture is closed. Lines below the logging level are not captured, as
+// they are not written
+func CaptureLogs() *Capture {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	c := &Capture{
+		stderr:   loggingStderr,
+		stdout:   loggingStdout,
+		w:        loggingW,
+		outputs:  loggingOutputs,
+		previous: loggingCapture,
+	}
+	loggingStderr = false
+	loggingStdout = false
+	loggingW = nil
+	loggingOutputs = nil
+	loggingCapture = c
+	return c
+}
+
+// add keeps the line, without the trailing newline
+func (c *Capture) add(level Level, line []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lines = append(c.lines, capturedLine{level: level, line: strings.TrimSuffix(string(line), "\n")})
+}
+
+// Lines returns the captured log lines, oldest first, without the trailing
+// newline
+func (c *Capture) Lines() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	lines := make([]string, len(c.lines))
+	for i, l := range c.lines {
+		lines[i] = l.line
+	}
+	return lines
+}
+
+// Contains reports whether a line of given level containing substr was
+// captured
+func (c *Capture) Contains(level Level, substr string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, l := range c.lines {
+		if l.level == level && strings.Contains(l.line, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset forgets the captured lines
+func (c *Capture) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lines = nil
+}
+
+// Close stops capturing, and restores the log destinations replaced by
+// CaptureLogs. The captured lines are kept
+func (c *Capture) Close() {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	if loggingCapture != c {
+		return
+	}
+	loggingStderr = c.stderr
+	loggingStdout = c.stdout
+	loggingW = c.w
+	loggingOutputs = c.outputs
+	loggingCapture = c.previous
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging capture", func() {
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		SetTimestamp(false)
+		SetLogLevel("debug")
+	})
+
+	It("Check the log lines are captured in place of the destinations", func() {
+		w := &writeRecorder{}
+		SetOutput(w)
+		c := CaptureLogs()
+		Debugf("debug %d", 1)
+		_ = Errorf("failed to attach net1")
+		gomega.Expect(c.Lines()).To(gomega.Equal([]string{"[debug] debug 1", "[error] failed to attach net1"}))
+		gomega.Expect(c.Contains(ErrorLevel, "attach net1")).To(gomega.BeTrue())
+		gomega.Expect(c.Contains(DebugLevel, "attach net1")).To(gomega.BeFalse())
+		gomega.Expect(c.Contains(ErrorLevel, "detach")).To(gomega.BeFalse())
+		gomega.Expect(w.writes).To(gomega.BeEmpty())
+
+		c.Reset()
+		gomega.Expect(c.Lines()).To(gomega.BeEmpty())
+
+		c.Close()
+		Verbosef("after close")
+		gomega.Expect(c.Lines()).To(gomega.BeEmpty())
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[verbose] after close\n"}))
+	})
+
+	It("Check nested captures restore the outer one", func() {
+		outer := CaptureLogs()
+		inner := CaptureLogs()
+		Verbosef("inner")
+		inner.Close()
+		Verbosef("outer")
+		outer.Close()
+		outer.Close()
+		gomega.Expect(inner.Lines()).To(gomega.Equal([]string{"[verbose] inner"}))
+		gomega.Expect(outer.Lines()).To(gomega.Equal([]string{"[verbose] outer"}))
+		gomega.Expect(loggingCapture).To(gomega.BeNil())
+		gomega.Expect(loggingStderr).To(gomega.BeFalse())
+	})
+})
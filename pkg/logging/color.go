@@ -0,0 +1,84 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+	"strings"
+)
+
+// colorMode type
+type colorMode uint32
+
+// colorAuto...colorNever indicates when stderr output is colorized
+const (
+	colorAuto colorMode = iota
+	colorAlways
+	colorNever
+)
+
+// ANSI escape sequences of the level colors
+const (
+	colorRed    = "\x1b[31m"
+	colorYellow = "\x1b[33m"
+	colorDim    = "\x1b[2m"
+	colorReset  = "\x1b[0m"
+)
+
+// SetColor sets when log lines written to stderr are colorized by level:
+// "auto" when stderr is a terminal, "always" or "never". Error, panic and
+// fatal lines are red, warning lines yellow, and debug and trace lines dim.
+// Other destinations, such as the log file, never get colors
+func SetColor(mode string) error {
+	var m colorMode
+	switch strings.ToLower(mode) {
+	case "auto":
+		m = colorAuto
+	case "always":
+		m = colorAlways
+	case "never":
+		m = colorNever
+	default:
+		return fmt.Errorf("unknown color mode %q, accepted modes are: auto, always, never", mode)
+	}
+
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingColor = m
+	return nil
+}
+
+// colorStderr reports whether stderr output is colorized. The caller must
+// hold loggingMutex
+func colorStderr() bool {
+	return loggingColor == colorAlways || (loggingColor == colorAuto && stderrTerminal)
+}
+
+// colorize returns the line in the color of the level
+func colorize(level Level, line []byte) []byte {
+	var color string
+	switch level {
+	case PanicLevel, ErrorLevel, FatalLevel:
+		color = colorRed
+	case WarningLevel:
+		color = colorYellow
+	case DebugLevel, TraceLevel:
+		color = colorDim
+	default:
+		return line
+	}
+	text := strings.TrimSuffix(string(line), "\n")
+	return []byte(color + text + colorReset + "\n")
+}
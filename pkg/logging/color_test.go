@@ -0,0 +1,113 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"io"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging stderr colors", func() {
+	var stderr *os.File
+	var r *os.File
+
+	BeforeEach(func() {
+		Reset()
+		SetLogLevel("debug")
+		SetTimestamp(false)
+
+		var w *os.File
+		var err error
+		r, w, err = os.Pipe()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		stderr = os.Stderr
+		os.Stderr = w
+		SetLogStderr(true)
+	})
+
+	AfterEach(func() {
+		os.Stderr.Close()
+		os.Stderr = stderr
+		r.Close()
+	})
+
+	// readStderr returns what was written to stderr
+	readStderr := func() string {
+		os.Stderr.Close()
+		out, err := io.ReadAll(r)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		return string(out)
+	}
+
+	It("Check the levels are colorized with always", func() {
+		gomega.Expect(SetColor("always")).To(gomega.Succeed())
+		_ = Errorf("error")
+		Warningf("warning")
+		Verbosef("verbose")
+		Debugf("debug")
+		gomega.Expect(readStderr()).To(gomega.Equal(
+			"\x1b[31m[error] error\x1b[0m\n" +
+				"\x1b[33m[warning] warning\x1b[0m\n" +
+				"[verbose] verbose\n" +
+				"\x1b[2m[debug] debug\x1b[0m\n"))
+	})
+
+	It("Check auto does not colorize a pipe", func() {
+		gomega.Expect(SetColor("auto")).To(gomega.Succeed())
+		_ = Errorf("error")
+		gomega.Expect(readStderr()).To(gomega.Equal("[error] error\n"))
+	})
+
+	It("Check the log file never gets colors", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		gomega.Expect(SetColor("Always")).To(gomega.Succeed())
+		_ = Errorf("error")
+		gomega.Expect(readStderr()).To(gomega.Equal("\x1b[31m[error] error\x1b[0m\n"))
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[error] error\n"}))
+
+		gomega.Expect(SetColor("never")).To(gomega.Succeed())
+		gomega.Expect(colorStderr()).To(gomega.BeFalse())
+	})
+
+	It("Check the stderr level only filters stderr", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		SetStderrLevel(ErrorLevel)
+		_ = Errorf("error")
+		Debugf("debug")
+		SetStderrLevel(UnknownLevel)
+		Verbosef("verbose")
+		gomega.Expect(readStderr()).To(gomega.Equal("[error] error\n[verbose] verbose\n"))
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[error] error\n", "[debug] debug\n", "[verbose] verbose\n"}))
+	})
+
+	It("Check StderrIsTerminal is false for a pipe", func() {
+		gomega.Expect(StderrIsTerminal()).To(gomega.BeFalse())
+
+		// the detection is cached until SetLogStderr is called
+		stderrTerminal = true
+		gomega.Expect(StderrIsTerminal()).To(gomega.BeTrue())
+		SetLogStderr(true)
+		gomega.Expect(StderrIsTerminal()).To(gomega.BeFalse())
+	})
+
+	It("Check an unknown color mode returns an error", func() {
+		gomega.Expect(SetColor("rainbow")).To(gomega.MatchError(`unknown color mode "rainbow", accepted modes are: auto, always, never`))
+	})
+})
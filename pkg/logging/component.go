@@ -0,0 +1,52 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+// SetComponentLevel sets the logging level of the log lines of the LogEntry
+// named component, overriding the logging level set by SetLogLevel. Invalid
+// levels are ignored
+func SetComponentLevel(component string, level Level) {
+	if level >= MaxLevel {
+		return
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	if loggingComponentLevels == nil {
+		loggingComponentLevels = make(map[string]Level)
+	}
+	loggingComponentLevels[component] = level
+	if level > loggingComponentMaxLevel.Load() {
+		loggingComponentMaxLevel.Store(level)
+	}
+}
+
+// ClearComponentLevels removes the logging levels set by SetComponentLevel
+func ClearComponentLevels() {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingComponentLevels = nil
+	loggingComponentMaxLevel.Store(PanicLevel)
+}
+
+// threshold returns the logging level of the log lines of e, the level of
+// its component when set. The caller must hold loggingMutex
+func threshold(e *LogEntry) Level {
+	if e != nil && e.component != "" {
+		if level, ok := loggingComponentLevels[e.component]; ok {
+			return level
+		}
+	}
+	return loggingLevel.Load()
+}
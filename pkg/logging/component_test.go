@@ -0,0 +1,57 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging component levels", func() {
+	var w *writeRecorder
+
+	BeforeEach(func() {
+		w = recordLogs(ErrorLevel)
+	})
+
+	It("Check components with and without a level override", func() {
+		SetComponentLevel("exec", DebugLevel)
+		SetComponentLevel("cache", PanicLevel)
+
+		Named("exec").Debugf("exec debug")
+		_ = Named("cache").Errorf("cache error")
+		Named("cache").Panicf("cache panic")
+		Named("other").Verbosef("other verbose")
+		_ = Named("other").Errorf("other error")
+		Debugf("global debug")
+		_ = Errorf("global error")
+
+		gomega.Expect(w.writes[0]).To(gomega.Equal("[debug] [exec] exec debug\n"))
+		gomega.Expect(w.writes[1]).To(gomega.Equal("[panic] [cache] cache panic\n"))
+		gomega.Expect(w.writes[len(w.writes)-2:]).To(gomega.Equal([]string{
+			"[error] [other] other error\n",
+			"[error] global error\n",
+		}))
+	})
+
+	It("Check ClearComponentLevels restores the logging level", func() {
+		SetComponentLevel("exec", DebugLevel)
+		SetComponentLevel("exec", UnknownLevel)
+		Named("exec").Debugf("exec debug")
+		ClearComponentLevels()
+		Named("exec").Debugf("filtered")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[debug] [exec] exec debug\n"}))
+	})
+})
@@ -0,0 +1,69 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"context"
+	"time"
+)
+
+// idKey is the context key of the correlation ID
+type idKey struct{}
+
+// ContextWithID returns a copy of ctx carrying the correlation ID id, which is
+// added to the log lines of the LogEntry returned by WithContext
+func ContextWithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+// IDFromContext returns the correlation ID carried by ctx, if any
+func IDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(idKey{}).(string)
+	return id, ok
+}
+
+// WithContext returns a LogEntry adding the id=<value> field to log messages,
+// when ctx carries a correlation ID. ctx is passed to the record sink as well
+func WithContext(ctx context.Context) *LogEntry {
+	return (&LogEntry{}).WithContext(ctx)
+}
+
+// WithContext returns a copy of the LogEntry adding the id=<value> field to
+// log messages, when ctx carries a correlation ID. ctx is passed to the record
+// sink as well, e.g. to correlate the log lines with traces
+func (e *LogEntry) WithContext(ctx context.Context) *LogEntry {
+	entry := *e
+	if id, ok := IDFromContext(ctx); ok {
+		entry = *e.WithField("id", id)
+	}
+	entry.ctx = ctx
+	return &entry
+}
+
+// LogIfNearDeadline prints a warning if logging level >= warning and ctx has
+// less than threshold left before its deadline, or is past it. The line gets
+// the correlation ID of ctx, as WithContext, and the remaining=<duration>
+// field. It does nothing when ctx has no deadline
+func LogIfNearDeadline(ctx context.Context, threshold time.Duration, format string, a ...interface{}) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+	remaining := deadline.Sub(nowFunc())
+	if remaining >= threshold {
+		return
+	}
+	printf(WithContext(ctx).WithField("remaining", remaining), WarningLevel, format, a...)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging with context", func() {
+
+	var w *writeRecorder
+
+	BeforeEach(func() {
+		w = recordLogs(DebugLevel)
+	})
+
+	It("Check correlation ID is added to log messages", func() {
+		ctx := ContextWithID(context.Background(), "7f3a")
+		id, ok := IDFromContext(ctx)
+		gomega.Expect(ok).To(gomega.BeTrue())
+		gomega.Expect(id).To(gomega.Equal("7f3a"))
+
+		WithContext(ctx).Debugf("foobar")
+		Named("server").WithContext(ctx).Verbosef("foobar")
+		gomega.Expect(w.writes[0]).To(gomega.HaveSuffix("[debug] foobar id=7f3a\n"))
+		gomega.Expect(w.writes[1]).To(gomega.HaveSuffix("[verbose] [server] foobar id=7f3a\n"))
+	})
+
+	It("Check context without ID changes nothing", func() {
+		WithContext(context.Background()).Debugf("foobar")
+		Debugf("foobar")
+		gomega.Expect(w.writes[0]).To(gomega.Equal("[debug] foobar\n"))
+		gomega.Expect(w.writes[1]).To(gomega.Equal(w.writes[0]))
+	})
+
+	It("Check a warning is logged near the context deadline", func() {
+		now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+		nowFunc = func() time.Time { return now }
+		defer func() { nowFunc = time.Now }()
+
+		LogIfNearDeadline(context.Background(), time.Hour, "no deadline")
+		ctx, cancel := context.WithDeadline(ContextWithID(context.Background(), "7f3a"), now.Add(2*time.Second))
+		defer cancel()
+		LogIfNearDeadline(ctx, time.Second, "far from the deadline")
+		gomega.Expect(w.writes).To(gomega.BeEmpty())
+
+		LogIfNearDeadline(ctx, 5*time.Second, "adding %s", "net1")
+		now = now.Add(3 * time.Second)
+		LogIfNearDeadline(ctx, time.Second, "past the deadline")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			"[warning] adding net1 id=7f3a remaining=2s\n",
+			"[warning] past the deadline id=7f3a remaining=-1s\n",
+		}))
+	})
+})
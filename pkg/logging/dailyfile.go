@@ -0,0 +1,192 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dailyFileDateLayout is the date in the name of the daily log files
+const dailyFileDateLayout = "2006-01-02"
+
+// dailyFile writes to a log file named after the current day, opening a new
+// one when the day changes
+type dailyFile struct {
+	mu     sync.Mutex
+	dir    string
+	prefix string
+	mode   os.FileMode
+	// maxAge is the number of days the log files are kept, zero keeps them
+	maxAge int
+	utc    bool
+	day    string
+	file   *os.File
+}
+
+func newDailyFile(dir, prefix string, mode os.FileMode, maxAge int, utc bool) *dailyFile {
+	return &dailyFile{
+		dir:    dir,
+		prefix: prefix,
+		mode:   mode,
+		maxAge: maxAge,
+		utc:    utc,
+	}
+}
+
+// filename returns the name of the log file of day
+func (d *dailyFile) filename(day string) string {
+	return filepath.Join(d.dir, d.prefix+"-"+day+".log")
+}
+
+// today returns the current day, in the layout of the file names. The caller
+// must hold d.mu
+func (d *dailyFile) today() string {
+	t := nowFunc()
+	if d.utc {
+		t = t.UTC()
+	}
+	return t.Format(dailyFileDateLayout)
+}
+
+func (d *dailyFile) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if day := d.today(); d.file == nil || day != d.day {
+		if err := d.open(day); err != nil {
+			return 0, err
+		}
+	}
+	return d.file.Write(p)
+}
+
+// open closes the current log file and opens the one of day. The caller must
+// hold d.mu
+func (d *dailyFile) open(day string) error {
+	if d.file != nil {
+		d.file.Close()
+		d.file = nil
+	}
+	perm := d.mode
+	if perm == 0 {
+		perm = 0644
+	}
+	filename := d.filename(day)
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, perm)
+	if err != nil {
+		return fmt.Errorf("failed to open log file %s: %w", filename, err)
+	}
+	if d.mode != 0 {
+		if err := f.Chmod(d.mode); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to set the mode of log file %s: %w", filename, err)
+		}
+	}
+	d.file = f
+	d.day = day
+	if d.maxAge > 0 {
+		go sweepDailyFiles(d.dir, d.prefix, day, d.maxAge)
+	}
+	return nil
+}
+
+// update sets the options of the log files opened from now on
+func (d *dailyFile) update(mode os.FileMode, maxAge int, utc bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.mode = mode
+	d.maxAge = maxAge
+	d.utc = utc
+}
+
+// Close closes the current log file. A later Write opens it again
+func (d *dailyFile) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.file == nil {
+		return nil
+	}
+	err := d.file.Close()
+	d.file = nil
+	return err
+}
+
+// sweepDailyFiles removes the daily log files in dir which are more than
+// maxAge days older than today. Files which are not named after a day are
+// left alone
+func sweepDailyFiles(dir, prefix, today string, maxAge int) {
+	current, err := time.Parse(dailyFileDateLayout, today)
+	if err != nil {
+		return
+	}
+	cutoff := current.AddDate(0, 0, -maxAge)
+	matches, err := filepath.Glob(filepath.Join(dir, prefix+"-*.log"))
+	if err != nil {
+		return
+	}
+	for _, name := range matches {
+		day := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(name), prefix+"-"), ".log")
+		t, err := time.Parse(dailyFileDateLayout, day)
+		if err != nil || !t.Before(cutoff) {
+			continue
+		}
+		os.Remove(name)
+	}
+}
+
+// SetDailyFile writes the log to a file in dir named prefix-YYYY-MM-DD.log
+// after the current day, in local time or UTC as set by SetUTC, so that a new
+// log file is started every day. The files older than the MaxAge days of
+// LogOptions are removed in the background when a new day starts. It replaces
+// the log file set by SetLogFile, and is replaced by it
+func SetDailyFile(dir, prefix string) error {
+	if prefix == "" {
+		return errors.New("cannot set the daily log file: prefix is empty")
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	ensureLogger()
+	d := newDailyFile(dir, prefix, loggingFileMode, logger.MaxAge, loggingUTC)
+	d.mu.Lock()
+	err := d.open(d.today())
+	d.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	closeDailyFile()
+	loggingDaily = d
+	loggingW = d
+	return nil
+}
+
+// closeDailyFile closes the daily log file, if any, and stops writing to it.
+// The caller must hold loggingMutex
+func closeDailyFile() error {
+	if loggingDaily == nil {
+		return nil
+	}
+	err := loggingDaily.Close()
+	if loggingW == io.Writer(loggingDaily) {
+		loggingW = nil
+	}
+	loggingDaily = nil
+	return err
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	testutils "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging daily file", func() {
+	var tmpDir string
+	var now time.Time
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		tmpDir = GinkgoT().TempDir()
+		now = time.Date(2023, 1, 2, 23, 59, 0, 0, time.Local)
+		nowFunc = func() time.Time { return now }
+		SetLogLevel("verbose")
+	})
+
+	AfterEach(func() {
+		gomega.Expect(Close()).To(gomega.Succeed())
+		nowFunc = time.Now
+	})
+
+	It("Check a new log file is opened when the day changes", func() {
+		gomega.Expect(SetDailyFile(tmpDir, "multus")).To(gomega.Succeed())
+		Verbosef("first day")
+		now = now.Add(2 * time.Minute)
+		Verbosef("second day")
+
+		data, err := os.ReadFile(filepath.Join(tmpDir, "multus-2023-01-02.log"))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(string(data)).To(gomega.ContainSubstring("first day"))
+		gomega.Expect(string(data)).NotTo(gomega.ContainSubstring("second day"))
+		data, err = os.ReadFile(filepath.Join(tmpDir, "multus-2023-01-03.log"))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(string(data)).To(gomega.ContainSubstring("second day"))
+	})
+
+	It("Check the old log files are removed", func() {
+		for _, name := range []string{"multus-2022-12-01.log", "multus-2022-12-30.log", "multus-backup.log"} {
+			gomega.Expect(os.WriteFile(filepath.Join(tmpDir, name), nil, 0644)).To(gomega.Succeed())
+		}
+		gomega.Expect(SetDailyFile(tmpDir, "multus")).To(gomega.Succeed())
+		gomega.Expect(SetLogOptions(&LogOptions{MaxAge: testutils.Int(5)})).To(gomega.Succeed())
+		now = now.Add(2 * time.Minute)
+		Verbosef("foobar")
+
+		gomega.Eventually(filepath.Join(tmpDir, "multus-2022-12-01.log")).ShouldNot(gomega.BeAnExistingFile())
+		gomega.Expect(filepath.Join(tmpDir, "multus-2022-12-30.log")).To(gomega.BeAnExistingFile())
+		gomega.Expect(filepath.Join(tmpDir, "multus-backup.log")).To(gomega.BeAnExistingFile())
+	})
+
+	It("Check the daily file is replaced by the log file", func() {
+		gomega.Expect(SetDailyFile(tmpDir, "")).NotTo(gomega.Succeed())
+		gomega.Expect(SetDailyFile(filepath.Join(tmpDir, "missing"), "multus")).To(gomega.MatchError(gomega.ContainSubstring("failed to open log file")))
+		gomega.Expect(loggingW).To(gomega.BeNil())
+
+		gomega.Expect(SetDailyFile(tmpDir, "multus")).To(gomega.Succeed())
+		gomega.Expect(SetLogFile(filepath.Join(tmpDir, "multus.log"))).To(gomega.Succeed())
+		gomega.Expect(loggingDaily).To(gomega.BeNil())
+		gomega.Expect(loggingW).To(gomega.Equal(logger))
+	})
+})
@@ -0,0 +1,74 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// debugJSONField is the field of the value of DebugJSON in the json format
+const debugJSONField = "value"
+
+// DebugJSON prints msg with v marshaled in json if logging level >= debug:
+// indented after "msg: " in the text and syslog formats, and compact as the
+// value field in the json format. v is not marshaled otherwise. A v which
+// cannot be marshaled is printed with %+v instead
+func DebugJSON(msg string, v interface{}) {
+	e, m, ok := withJSON(nil, msg, v)
+	if !ok {
+		return
+	}
+	printf(e, DebugLevel, "%s", m)
+}
+
+// DebugJSON prints msg with v marshaled in json if logging level >= debug,
+// see DebugJSON
+func (e *LogEntry) DebugJSON(msg string, v interface{}) {
+	e, m, ok := withJSON(e, msg, v)
+	if !ok {
+		return
+	}
+	printf(e, DebugLevel, "%s", m)
+}
+
+// withJSON returns the LogEntry and the message of DebugJSON, or false when
+// debug messages of e are not logged
+func withJSON(e *LogEntry, msg string, v interface{}) (*LogEntry, string, bool) {
+	loggingMutex.RLock()
+	enabled := logEnabled(e, DebugLevel)
+	format := loggingFormat
+	loggingMutex.RUnlock()
+	if !enabled {
+		return nil, "", false
+	}
+	if e == nil {
+		e = &LogEntry{}
+	}
+
+	// v is marshaled without loggingMutex, as its MarshalJSON may log
+	if format == jsonFormat {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return e.WithField(debugJSONField, fmt.Sprintf("%+v", v)), msg, true
+		}
+		return e.WithField(debugJSONField, json.RawMessage(data)), msg, true
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return e, fmt.Sprintf("%s: %+v", msg, v), true
+	}
+	return e, msg + ": " + string(data), true
+}
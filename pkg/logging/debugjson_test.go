@@ -0,0 +1,82 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+// countingMarshaler counts its marshalings
+type countingMarshaler struct {
+	calls *int
+}
+
+func (m countingMarshaler) MarshalJSON() ([]byte, error) {
+	*m.calls++
+	return []byte(`{"b":[1,2]}`), nil
+}
+
+var _ = Describe("logging json values", func() {
+	var w *writeRecorder
+
+	BeforeEach(func() {
+		w = recordLogs(DebugLevel)
+	})
+
+	It("Check the value is indented in text format", func() {
+		DebugJSON("netconf", map[string]interface{}{"name": "net1", "ips": []string{"10.0.0.1"}})
+		Named("cache").WithField("a", 1).DebugJSON("netconf", "net1")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			"[debug] netconf: {\n  \"ips\": [\n    \"10.0.0.1\"\n  ],\n  \"name\": \"net1\"\n}\n",
+			"[debug] [cache] netconf: \"net1\" a=1\n",
+		}))
+	})
+
+	It("Check the value is a nested field in json format", func() {
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		calls := 0
+		Named("cache").DebugJSON("netconf", countingMarshaler{&calls})
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			`{"level":"debug","component":"cache","msg":"netconf","value":{"b":[1,2]},"schemaVersion":"1"}` + "\n",
+		}))
+	})
+
+	It("Check the value is not marshaled below the debug level", func() {
+		calls := 0
+		loggingLevel.Store(VerboseLevel)
+		DebugJSON("netconf", countingMarshaler{&calls})
+		SetComponentLevel("cache", DebugLevel)
+		Named("cache").DebugJSON("netconf", countingMarshaler{&calls})
+		gomega.Expect(calls).To(gomega.Equal(1))
+		gomega.Expect(w.writes).To(gomega.HaveLen(1))
+	})
+
+	It("Check a value which cannot be marshaled is formatted", func() {
+		DebugJSON("value", struct{ C chan int }{})
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		DebugJSON("value", struct{ C chan int }{})
+		gomega.Expect(w.writes[0]).To(gomega.Equal("[debug] value: {C:<nil>}\n"))
+		gomega.Expect(w.writes[1]).To(gomega.ContainSubstring(`"msg":"value","value":"{C:<nil>}"`))
+	})
+
+	It("Check the caller of DebugJSON is reported", func() {
+		SetLogCaller(true)
+		DebugJSON("value", 1)
+		Named("cache").DebugJSON("value", 1)
+		gomega.Expect(w.writes[0]).To(gomega.MatchRegexp(`^\[debug\] debugjson_test\.go:\d+ value: 1\n$`))
+		gomega.Expect(w.writes[1]).To(gomega.MatchRegexp(`^\[debug\] \[cache\] debugjson_test\.go:\d+ value: 1\n$`))
+	})
+})
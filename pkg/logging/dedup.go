@@ -0,0 +1,80 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// deduplicator collapses a log message repeated within a time window
+type deduplicator struct {
+	mutex    sync.Mutex
+	window   time.Duration
+	last     *record
+	lastKey  string
+	repeated int
+}
+
+// key identifies the message of a record, regardless of its time
+func (r *record) key() string {
+	key := fmt.Sprintf("%d\x00%s\x00%s", r.level, r.component, r.msg)
+	for _, k := range r.sortedKeys() {
+		key += fmt.Sprintf("\x00%s=%v", k, r.fields[k])
+	}
+	return key
+}
+
+// check reports whether r repeats the last message within the window. When r
+// is not a duplicate and the last message was repeated, it also returns the
+// record reporting the repetitions, to be logged before r
+func (d *deduplicator) check(r *record) (*record, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	key := r.key()
+	if d.last != nil && key == d.lastKey && r.time.Sub(d.last.time) < d.window {
+		d.repeated++
+		return nil, true
+	}
+
+	var repeated *record
+	if d.repeated > 0 {
+		repeated = &record{
+			time:      r.time,
+			level:     d.last.level,
+			component: d.last.component,
+			msg:       fmt.Sprintf("previous message repeated %d times", d.repeated),
+		}
+	}
+	d.last = r
+	d.lastKey = key
+	d.repeated = 0
+	return repeated, false
+}
+
+// SetDedup collapses identical log messages logged within window of the
+// first one. The number of repetitions is logged along with the next distinct
+// message. Deduplication happens before rate limiting (see SetRateLimit), so
+// that repetitions do not use up the rate. Zero or less window disables it
+func SetDedup(window time.Duration) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	if window <= 0 {
+		loggingDedup = nil
+		return
+	}
+	loggingDedup = &deduplicator{window: window}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging deduplication", func() {
+
+	var w *writeRecorder
+	var now time.Time
+
+	BeforeEach(func() {
+		w = recordLogs(DebugLevel)
+		now = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+		nowFunc = func() time.Time { return now }
+	})
+
+	AfterEach(func() {
+		nowFunc = time.Now
+	})
+
+	It("Check repeated messages are collapsed", func() {
+		SetDedup(time.Minute)
+		for i := 0; i < 4; i++ {
+			Debugf("foo %s", "bar")
+		}
+		Verbosef("foo bar")
+		Verbosef("baz")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			"[debug] foo bar\n",
+			"[debug] previous message repeated 3 times\n",
+			"[verbose] foo bar\n",
+			"[verbose] baz\n",
+		}))
+	})
+
+	It("Check messages are repeated once the window is over", func() {
+		SetDedup(time.Second)
+		Debugf("foobar")
+		Debugf("foobar")
+		now = now.Add(time.Second)
+		Debugf("foobar")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			"[debug] foobar\n",
+			"[debug] previous message repeated 1 times\n",
+			"[debug] foobar\n",
+		}))
+	})
+
+	It("Check fields and components make messages distinct", func() {
+		SetDedup(time.Minute)
+		Debugf("foobar")
+		Named("cache").Debugf("foobar")
+		WithField("a", 1).Debugf("foobar")
+		gomega.Expect(w.writes).To(gomega.HaveLen(3))
+	})
+
+	It("Check repetitions do not use up the rate limit", func() {
+		SetDedup(time.Minute)
+		SetRateLimit(1, 2)
+		for i := 0; i < 10; i++ {
+			Debugf("foobar")
+		}
+		Debugf("baz")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			"[debug] foobar\n",
+			"[debug] previous message repeated 9 times\n",
+			"[debug] baz\n",
+		}))
+	})
+
+	It("Check deduplication can be disabled", func() {
+		SetDedup(time.Minute)
+		SetDedup(0)
+		gomega.Expect(loggingDedup).To(gomega.BeNil())
+		Debugf("foobar")
+		Debugf("foobar")
+		gomega.Expect(w.writes).To(gomega.HaveLen(2))
+	})
+})
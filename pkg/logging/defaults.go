@@ -0,0 +1,85 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	lumberjack "gopkg.in/natefinch/lumberjack.v2"
+)
+
+// fileDefaults are the options of the log file which are not set by
+// SetLogOptions
+type fileDefaults struct {
+	maxAge     int
+	maxSize    int
+	maxBackups int
+	compress   bool
+}
+
+// builtinFileDefaults are the historical defaults of SetLogOptions
+var builtinFileDefaults = fileDefaults{
+	maxAge:     5,
+	maxSize:    100,
+	maxBackups: 5,
+	compress:   true,
+}
+
+// apply sets the options of l to the defaults
+func (d fileDefaults) apply(l *lumberjack.Logger) {
+	l.MaxAge = d.maxAge
+	l.MaxSize = d.maxSize
+	l.MaxBackups = d.maxBackups
+	l.Compress = d.compress
+}
+
+// SetDefaults sets the defaults of the MaxAge, MaxSize, MaxBackups and
+// Compress log options: 5 days, 100 megabytes, 5 backups and compressed
+// unless set. SetLogOptions uses them for the options it is not given, and,
+// when enabled by SetLogFileDefaults, SetLogFile for all of them until
+// SetLogOptions is called. The other options of opts are ignored. It returns
+// an error, and keeps the current defaults, when an option is out of range
+func SetDefaults(opts LogOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	d := builtinFileDefaults
+	if opts.MaxAge != nil {
+		d.maxAge = *opts.MaxAge
+	}
+	if opts.MaxSize != nil {
+		d.maxSize = *opts.MaxSize
+	}
+	if opts.MaxBackups != nil {
+		d.maxBackups = *opts.MaxBackups
+	}
+	if opts.Compress != nil {
+		d.compress = *opts.Compress
+	}
+
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingFileDefaults = d
+	return nil
+}
+
+// SetLogFileDefaults sets flag for SetLogFile called before SetLogOptions to
+// use the defaults of SetDefaults. It is disabled by default, for the
+// historical behavior: the log file is neither compressed nor limited in age
+// and number of backups, and rotated at 100 megabytes, until SetLogOptions is
+// called
+func SetLogFileDefaults(enable bool) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingFileUsesDefaults = enable
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"path/filepath"
+
+	testutils "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/testing"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging file defaults", func() {
+	var logFile string
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		logFile = filepath.Join(GinkgoT().TempDir(), "multus.log")
+	})
+
+	AfterEach(func() {
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	It("Check SetLogFile before SetLogOptions keeps the historical options", func() {
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
+		gomega.Expect(logger).To(gomega.Equal(&lumberjack.Logger{Filename: logFile}))
+		gomega.Expect(SetLogOptions(&LogOptions{MaxBackups: testutils.Int(1)})).To(gomega.Succeed())
+		gomega.Expect(logger).To(gomega.Equal(&lumberjack.Logger{Filename: logFile, MaxAge: 5, MaxSize: 100, MaxBackups: 1, Compress: true}))
+	})
+
+	It("Check SetLogFile after SetLogOptions keeps the options", func() {
+		gomega.Expect(SetLogOptions(&LogOptions{MaxBackups: testutils.Int(1), Compress: testutils.Bool(false)})).To(gomega.Succeed())
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
+		gomega.Expect(logger).To(gomega.Equal(&lumberjack.Logger{Filename: logFile, MaxAge: 5, MaxSize: 100, MaxBackups: 1}))
+	})
+
+	It("Check both setters use the defaults set", func() {
+		SetLogFileDefaults(true)
+		gomega.Expect(SetDefaults(LogOptions{MaxAge: testutils.Int(1), Compress: testutils.Bool(false)})).To(gomega.Succeed())
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
+		gomega.Expect(logger).To(gomega.Equal(&lumberjack.Logger{Filename: logFile, MaxAge: 1, MaxSize: 100, MaxBackups: 5}))
+		gomega.Expect(SetLogOptions(&LogOptions{MaxSize: testutils.Int(10)})).To(gomega.Succeed())
+		gomega.Expect(logger).To(gomega.Equal(&lumberjack.Logger{Filename: logFile, MaxAge: 1, MaxSize: 10, MaxBackups: 5}))
+
+		gomega.Expect(SetDefaults(LogOptions{MaxAge: testutils.Int(-1)})).To(gomega.MatchError("invalid log option maxAge: -1, must not be negative"))
+		gomega.Expect(loggingFileDefaults.maxAge).To(gomega.Equal(1))
+	})
+
+	It("Check SetLogFile before SetLogOptions uses the defaults when enabled", func() {
+		SetLogFileDefaults(true)
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
+		gomega.Expect(logger).To(gomega.Equal(&lumberjack.Logger{Filename: logFile, MaxAge: 5, MaxSize: 100, MaxBackups: 5, Compress: true}))
+		gomega.Expect(SetLogOptions(&LogOptions{MaxBackups: testutils.Int(1)})).To(gomega.Succeed())
+		gomega.Expect(logger).To(gomega.Equal(&lumberjack.Logger{Filename: logFile, MaxAge: 5, MaxSize: 100, MaxBackups: 1, Compress: true}))
+	})
+})
@@ -0,0 +1,267 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// The fields annotating log lines with the pod, network namespace and
+// interface they are about
+const (
+	podField   = "pod"
+	netnsField = "netns"
+	ifaceField = "iface"
+)
+
+// The fields annotating log lines with an error
+const (
+	errorField      = "error"
+	errorChainField = "errorChain"
+)
+
+// errorChain is the messages of an error and of the errors it wraps, which
+// only json log lines render
+type errorChain []string
+
+// LogEntry is a logger annotating its log lines, e.g. with the name of the
+// Multus component logging them or with key=value fields. It shares the
+// logging level and the destinations of the package level functions
+type LogEntry struct {
+	component string
+	fields    map[string]interface{}
+	// callDepth is the number of stack frames between the code logging and
+	// the LogEntry methods, for adapters of other logging libraries
+	callDepth int
+	// pc is the program counter of the code logging, for adapters given it
+	// with the log record, which is then used rather than callDepth
+	pc uintptr
+	// ctx is the context of WithContext, passed to the record sink
+	ctx context.Context
+}
+
+// Named returns a LogEntry prefixing log messages with [component]
+func Named(component string) *LogEntry {
+	return &LogEntry{component: component}
+}
+
+// WithFields returns a LogEntry adding fields to log messages
+func WithFields(fields map[string]interface{}) *LogEntry {
+	return (&LogEntry{}).WithFields(fields)
+}
+
+// WithField returns a LogEntry adding the key=value field to log messages
+func WithField(key string, value interface{}) *LogEntry {
+	return (&LogEntry{}).WithField(key, value)
+}
+
+// WithFields returns a copy of the LogEntry with fields added
+func (e *LogEntry) WithFields(fields map[string]interface{}) *LogEntry {
+	merged := make(map[string]interface{}, len(e.fields)+len(fields))
+	for k, v := range e.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	entry := *e
+	entry.fields = merged
+	return &entry
+}
+
+// SetMaxFields sets the maximum number of fields of a log line, 100 by
+// default. The fields after the first n in sorted order are dropped and the
+// line gets a _truncated=true field instead. n <= 0 removes the limit
+func SetMaxFields(n int) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingMaxFields = n
+}
+
+// withError returns a copy of the LogEntry with the error and errorChain
+// fields of err added, or the LogEntry itself when err is nil
+func (e *LogEntry) withError(err error) *LogEntry {
+	if err == nil {
+		return e
+	}
+	var chain errorChain
+	for u := err; u != nil; u = errors.Unwrap(u) {
+		chain = append(chain, u.Error())
+	}
+	return e.WithFields(map[string]interface{}{
+		errorField:      err.Error(),
+		errorChainField: chain,
+	})
+}
+
+// WithField returns a copy of the LogEntry with the key=value field added
+func (e *LogEntry) WithField(key string, value interface{}) *LogEntry {
+	return e.WithFields(map[string]interface{}{key: value})
+}
+
+// ForPod returns a LogEntry annotating log messages with the pod
+// pod=namespace/name
+func ForPod(namespace, name string) *LogEntry {
+	return (&LogEntry{}).ForPod(namespace, name)
+}
+
+// ForNetns returns a LogEntry annotating log messages with the network
+// namespace netns=netns
+func ForNetns(netns string) *LogEntry {
+	return (&LogEntry{}).ForNetns(netns)
+}
+
+// ForInterface returns a LogEntry annotating log messages with the
+// interface iface=ifname
+func ForInterface(ifname string) *LogEntry {
+	return (&LogEntry{}).ForInterface(ifname)
+}
+
+// ForPod returns a copy of the LogEntry annotated with the pod
+func (e *LogEntry) ForPod(namespace, name string) *LogEntry {
+	return e.WithField(podField, namespace+"/"+name)
+}
+
+// ForNetns returns a copy of the LogEntry annotated with the network
+// namespace
+func (e *LogEntry) ForNetns(netns string) *LogEntry {
+	return e.WithField(netnsField, netns)
+}
+
+// ForInterface returns a copy of the LogEntry annotated with the interface
+func (e *LogEntry) ForInterface(ifname string) *LogEntry {
+	return e.WithField(ifaceField, ifname)
+}
+
+// Logf prints logging if logging level >= level, as the package Logf
+func (e *LogEntry) Logf(level Level, format string, a ...interface{}) {
+	if !level.valid() {
+		return
+	}
+	printf(e, level, format, a...)
+}
+
+// Raw prints msg as is if logging level >= level, as the package Raw
+func (e *LogEntry) Raw(level Level, msg string) {
+	if !level.valid() {
+		return
+	}
+	printf(e, level, "%s", msg)
+}
+
+// Trace prints msg as is if logging level >= trace, as the package Trace
+func (e *LogEntry) Trace(msg string) {
+	printf(e, TraceLevel, "%s", msg)
+}
+
+// Debug prints msg as is if logging level >= debug
+func (e *LogEntry) Debug(msg string) {
+	printf(e, DebugLevel, "%s", msg)
+}
+
+// Verbose prints msg as is if logging level >= verbose
+func (e *LogEntry) Verbose(msg string) {
+	printf(e, VerboseLevel, "%s", msg)
+}
+
+// Info prints msg as is if logging level >= info, which is verbose
+func (e *LogEntry) Info(msg string) {
+	printf(e, InfoLevel, "%s", msg)
+}
+
+// Warning prints msg as is if logging level >= warning
+func (e *LogEntry) Warning(msg string) {
+	printf(e, WarningLevel, "%s", msg)
+}
+
+// Error prints msg as is if logging level >= error, and returns an error
+// with msg as its message, as the package Error
+func (e *LogEntry) Error(msg string) error {
+	printf(e, ErrorLevel, "%s", msg)
+	return errors.New(msg)
+}
+
+// Tracef prints logging if logging level >= trace
+func (e *LogEntry) Tracef(format string, a ...interface{}) {
+	printf(e, TraceLevel, format, a...)
+}
+
+// Debugf prints logging if logging level >= debug
+func (e *LogEntry) Debugf(format string, a ...interface{}) {
+	printf(e, DebugLevel, format, a...)
+}
+
+// Verbosef prints logging if logging level >= verbose
+func (e *LogEntry) Verbosef(format string, a ...interface{}) {
+	printf(e, VerboseLevel, format, a...)
+}
+
+// Infof prints logging if logging level >= info, which is verbose
+func (e *LogEntry) Infof(format string, a ...interface{}) {
+	printf(e, InfoLevel, format, a...)
+}
+
+// Warningf prints logging if logging level >= warning
+func (e *LogEntry) Warningf(format string, a ...interface{}) {
+	printf(e, WarningLevel, format, a...)
+}
+
+// Errorf prints logging if logging level >= error. The returned error wraps
+// the argument of a %w verb, like fmt.Errorf
+func (e *LogEntry) Errorf(format string, a ...interface{}) error {
+	err := fmt.Errorf(format, a...)
+	printf(e, ErrorLevel, "%s", err)
+	return err
+}
+
+// WrapError prints logging if logging level >= error, and returns an error
+// wrapping err, annotated with the message
+func (e *LogEntry) WrapError(err error, format string, a ...interface{}) error {
+	wrapped := fmt.Errorf("%s: %w", fmt.Sprintf(format, a...), err)
+	printf(e, ErrorLevel, "%s", wrapped)
+	return wrapped
+}
+
+// ErrorErr prints logging if logging level >= error, annotated with err as
+// the ErrorErr package function, and returns err
+func (e *LogEntry) ErrorErr(err error, format string, a ...interface{}) error {
+	printf(e.withError(err), ErrorLevel, format, a...)
+	return err
+}
+
+// Panicf prints logging plus stack trace. This should be used only for unrecoverable error
+func (e *LogEntry) Panicf(format string, a ...interface{}) {
+	printf(e, PanicLevel, format, a...)
+	banners, trace := stack(e.callDepth)
+	if banners {
+		printf(e, PanicLevel, "========= Stack trace output ========")
+	}
+	printf(e, PanicLevel, "%s", trace)
+	if banners {
+		printf(e, PanicLevel, "========= Stack trace output end ========")
+	}
+}
+
+// Fatalf prints logging and exits the process with status 1. This should be used
+// only when Multus cannot continue
+func (e *LogEntry) Fatalf(format string, a ...interface{}) {
+	printf(e, FatalLevel, format, a...)
+	// the inline compressed log file is finished, so that the fatal line can
+	// be read from it
+	_ = Flush()
+	exitFunc(1)
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging entries", func() {
+
+	var w *writeRecorder
+
+	BeforeEach(func() {
+		w = recordLogs(DebugLevel)
+	})
+
+	It("Check named entry prefixes the component", func() {
+		cache := Named("cache")
+		cache.Debugf("foo %s", "bar")
+		cache.Verbosef("foobar")
+		cache.Warningf("foobar")
+		gomega.Expect(cache.Errorf("foobar")).To(gomega.MatchError("foobar"))
+		gomega.Expect(w.writes).To(gomega.HaveLen(4))
+		gomega.Expect(w.writes[0]).To(gomega.HaveSuffix("[debug] [cache] foo bar\n"))
+		gomega.Expect(w.writes[1]).To(gomega.HaveSuffix("[verbose] [cache] foobar\n"))
+		gomega.Expect(w.writes[2]).To(gomega.HaveSuffix("[warning] [cache] foobar\n"))
+		gomega.Expect(w.writes[3]).To(gomega.HaveSuffix("[error] [cache] foobar\n"))
+
+		cause := errors.New("cause")
+		gomega.Expect(errors.Is(cache.WrapError(cause, "foobar"), cause)).To(gomega.BeTrue())
+	})
+
+	It("Check named entry shares the logging level", func() {
+		loggingLevel.Store(ErrorLevel)
+		Named("cache").Debugf("foobar")
+		gomega.Expect(w.writes).To(gomega.BeEmpty())
+	})
+
+	It("Check named entry panic and fatal", func() {
+		exitCode := -1
+		exitFunc = func(code int) { exitCode = code }
+		defer func() { exitFunc = os.Exit }()
+
+		Named("cache").Panicf("foobar")
+		gomega.Expect(w.writes).To(gomega.HaveLen(4))
+		gomega.Expect(w.writes[2]).To(gomega.ContainSubstring("[panic] [cache] goroutine "))
+		Named("cache").Fatalf("foobar")
+		gomega.Expect(exitCode).To(gomega.Equal(1))
+		gomega.Expect(w.writes[4]).To(gomega.HaveSuffix("[fatal] [cache] foobar\n"))
+	})
+
+	It("Check named entry with caller and json format", func() {
+		SetLogCaller(true)
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		Named("cache").Debugf("foobar")
+		gomega.Expect(w.writes[0]).To(gomega.MatchRegexp(`"component":"cache","caller":"entry_test\.go:\d+","msg":"foobar"`))
+	})
+
+	It("Check fields are appended sorted in text format", func() {
+		WithField("netns", "/var/run/netns/foo").WithFields(map[string]interface{}{
+			"ifname": "net1",
+			"count":  3,
+			"empty":  "",
+		}).Debugf("attaching %s", "net1")
+		gomega.Expect(w.writes[0]).To(gomega.HaveSuffix(`[debug] attaching net1 count=3 empty="" ifname=net1 netns=/var/run/netns/foo` + "\n"))
+
+		Named("cache").WithField("msg", "a b").Verbosef("foobar")
+		gomega.Expect(w.writes[1]).To(gomega.HaveSuffix(`[verbose] [cache] foobar msg="a b"` + "\n"))
+	})
+
+	It("Check entries annotated with the pod, netns and interface", func() {
+		pod := ForPod("default", "web-0")
+		pod.ForNetns("/var/run/netns/foo").ForInterface("net1").Verbosef("attaching")
+		gomega.Expect(w.writes[0]).To(gomega.HaveSuffix(`[verbose] attaching iface=net1 netns=/var/run/netns/foo pod=default/web-0` + "\n"))
+		ForInterface("eth0").Verbosef("up")
+		gomega.Expect(w.writes[1]).To(gomega.HaveSuffix(`[verbose] up iface=eth0` + "\n"))
+
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		pod.Verbosef("deleted")
+		record := map[string]interface{}{}
+		gomega.Expect(json.Unmarshal([]byte(w.writes[2]), &record)).To(gomega.Succeed())
+		gomega.Expect(record).To(gomega.HaveKeyWithValue("pod", "default/web-0"))
+		gomega.Expect(record).NotTo(gomega.HaveKey("iface"))
+	})
+
+	It("Check ErrorErr annotates the log line with the error", func() {
+		cause := errors.New("no such file")
+		err := fmt.Errorf("open /etc/cni: %w", cause)
+		gomega.Expect(ErrorErr(err, "failed to load %s", "netconf")).To(gomega.BeIdenticalTo(err))
+		gomega.Expect(w.writes[0]).To(gomega.HaveSuffix(`[error] failed to load netconf error="open /etc/cni: no such file"` + "\n"))
+
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		gomega.Expect(Named("cache").ErrorErr(err, "failed")).To(gomega.BeIdenticalTo(err))
+		record := map[string]interface{}{}
+		gomega.Expect(json.Unmarshal([]byte(w.writes[1]), &record)).To(gomega.Succeed())
+		gomega.Expect(record).To(gomega.HaveKeyWithValue("component", "cache"))
+		gomega.Expect(record).To(gomega.HaveKeyWithValue("msg", "failed"))
+		gomega.Expect(record).To(gomega.HaveKeyWithValue("error", "open /etc/cni: no such file"))
+		gomega.Expect(record).To(gomega.HaveKeyWithValue("errorChain", []interface{}{"open /etc/cni: no such file", "no such file"}))
+
+		gomega.Expect(ErrorErr(nil, "no error")).To(gomega.Succeed())
+		gomega.Expect(w.writes[2]).To(gomega.ContainSubstring(`"msg":"no error","schemaVersion":"1"}`))
+	})
+
+	It("Check fields are json keys in json format", func() {
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		Named("cache").WithField("ifname", "net1").WithField("level", 3).WithField("ch", make(chan int)).Debugf("foo<bar>")
+		record := map[string]interface{}{}
+		gomega.Expect(json.Unmarshal([]byte(w.writes[0]), &record)).To(gomega.Succeed())
+		gomega.Expect(record).To(gomega.HaveKeyWithValue("level", "debug"))
+		gomega.Expect(record).To(gomega.HaveKeyWithValue("component", "cache"))
+		gomega.Expect(record).To(gomega.HaveKeyWithValue("msg", "foo<bar>"))
+		gomega.Expect(record).To(gomega.HaveKeyWithValue("ifname", "net1"))
+		gomega.Expect(record).To(gomega.HaveKeyWithValue("fields.level", 3.0))
+		gomega.Expect(record).To(gomega.HaveKey("ch"))
+		gomega.Expect(w.writes[0]).To(gomega.HavePrefix(`{"level":"debug","component":"cache","msg":"foo<bar>","ch":`))
+	})
+
+	It("Check the fields are truncated to the maximum", func() {
+		fields := map[string]interface{}{}
+		for i := 0; i < defaultMaxFields+1; i++ {
+			fields[fmt.Sprintf("f%03d", i)] = i
+		}
+		WithFields(fields).Debugf("foobar")
+		gomega.Expect(w.writes[0]).To(gomega.HaveSuffix(" f099=99 _truncated=true\n"))
+
+		SetMaxFields(2)
+		WithField("c", 3).WithField("b", 2).WithField("a", 1).Debugf("foobar")
+		gomega.Expect(w.writes[1]).To(gomega.HaveSuffix("[debug] foobar a=1 b=2 _truncated=true\n"))
+		WithField("b", 2).WithField("a", 1).Debugf("foobar")
+		gomega.Expect(w.writes[2]).To(gomega.HaveSuffix("[debug] foobar a=1 b=2\n"))
+
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		WithField("c", 3).WithField("b", 2).WithField("a", 1).Debugf("foobar")
+		gomega.Expect(w.writes[3]).To(gomega.Equal(`{"level":"debug","msg":"foobar","a":1,"b":2,"_truncated":true,"schemaVersion":"1"}` + "\n"))
+
+		SetMaxFields(0)
+		WithFields(fields).Debugf("foobar")
+		gomega.Expect(w.writes[4]).To(gomega.HaveSuffix(`"f100":100,"schemaVersion":"1"}` + "\n"))
+	})
+
+	It("Check fields do not leak into the parent entry", func() {
+		parent := WithField("a", 1)
+		parent.WithField("b", 2).Debugf("child")
+		parent.Debugf("parent")
+		gomega.Expect(w.writes[0]).To(gomega.HaveSuffix("child a=1 b=2\n"))
+		gomega.Expect(w.writes[1]).To(gomega.HaveSuffix("parent a=1\n"))
+	})
+})
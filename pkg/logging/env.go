@@ -0,0 +1,149 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Environment variables read by ConfigureFromEnv
+const (
+	EnvLogLevel          = "MULTUS_LOG_LEVEL"
+	EnvLogFile           = "MULTUS_LOG_FILE"
+	EnvLogToStderr       = "MULTUS_LOG_TO_STDERR"
+	EnvLogMaxSize        = "MULTUS_LOG_MAX_SIZE"
+	EnvLogMaxAge         = "MULTUS_LOG_MAX_AGE"
+	EnvLogMaxBackups     = "MULTUS_LOG_MAX_BACKUPS"
+	EnvLogCompress       = "MULTUS_LOG_COMPRESS"
+	EnvLogMaxTotalSize   = "MULTUS_LOG_MAX_TOTAL_SIZE"
+	EnvLogFileMode       = "MULTUS_LOG_FILE_MODE"
+	EnvLogCompressFormat = "MULTUS_LOG_COMPRESS_FORMAT"
+)
+
+// ConfigureFromEnv configures logging from the MULTUS_LOG_* environment
+// variables. Unset or empty variables keep their setting at its default. The
+// variables are all checked before any is applied, in the order of
+// ConfigureFromNetConf. It returns the errors joined, and configures nothing,
+// when a variable has an invalid value
+func ConfigureFromEnv() error {
+	var (
+		errs        []error
+		stderr      *bool
+		options     LogOptions
+		haveOptions bool
+		err         error
+	)
+
+	level := os.Getenv(EnvLogLevel)
+	if level != "" {
+		if _, err := ParseLevel(level); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %w", EnvLogLevel, err))
+		}
+	}
+	if stderr, err = envBool(EnvLogToStderr); err != nil {
+		errs = append(errs, err)
+	}
+	for _, opt := range []struct {
+		name  string
+		value **int
+	}{
+		{EnvLogMaxSize, &options.MaxSize},
+		{EnvLogMaxAge, &options.MaxAge},
+		{EnvLogMaxBackups, &options.MaxBackups},
+		{EnvLogMaxTotalSize, &options.MaxTotalSize},
+	} {
+		if *opt.value, err = envInt(opt.name); err != nil {
+			errs = append(errs, err)
+		}
+		haveOptions = haveOptions || *opt.value != nil
+	}
+	if options.Compress, err = envBool(EnvLogCompress); err != nil {
+		errs = append(errs, err)
+	}
+	haveOptions = haveOptions || options.Compress != nil
+	if s := os.Getenv(EnvLogFileMode); s != "" {
+		options.FileMode = &s
+		haveOptions = true
+	}
+	if s := os.Getenv(EnvLogCompressFormat); s != "" {
+		options.CompressFormat = &s
+		haveOptions = true
+	}
+	if haveOptions {
+		if err := options.validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	// checking the log file creates it, so it is only checked when the other
+	// variables are valid
+	file := os.Getenv(EnvLogFile)
+	if file != "" && len(errs) == 0 {
+		if err := checkWritable(file, envFileMode(&options)); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %w", EnvLogFile, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	opts := &options
+	if !haveOptions {
+		opts = nil
+	}
+	return configure(stderr, opts, file, level)
+}
+
+// envFileMode returns the mode the log file gets with options, the current one
+// when options do not set it
+func envFileMode(options *LogOptions) os.FileMode {
+	if options.FileMode != nil {
+		mode, _ := parseFileMode(*options.FileMode)
+		return mode
+	}
+	loggingMutex.RLock()
+	defer loggingMutex.RUnlock()
+	return loggingFileMode
+}
+
+// envBool returns the boolean value of environment variable name, or nil
+// when it is not set
+func envBool(name string) (*bool, error) {
+	s := os.Getenv(name)
+	if s == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: must be a boolean", name, s)
+	}
+	return &b, nil
+}
+
+// envInt returns the integer value of environment variable name, or nil
+// when it is not set
+func envInt(name string) (*int, error) {
+	s := os.Getenv(name)
+	if s == "" {
+		return nil, nil
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: must be an integer", name, s)
+	}
+	return &i, nil
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"path/filepath"
+
+	testutils "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging configuration from the environment", func() {
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		for _, name := range []string{EnvLogLevel, EnvLogFile, EnvLogToStderr, EnvLogMaxSize, EnvLogMaxAge, EnvLogMaxBackups, EnvLogCompress, EnvLogMaxTotalSize, EnvLogFileMode} {
+			GinkgoT().Setenv(name, "")
+		}
+	})
+
+	It("Check unset variables keep the defaults", func() {
+		gomega.Expect(ConfigureFromEnv()).To(gomega.Succeed())
+		gomega.Expect(GetLoggingLevel()).To(gomega.Equal(PanicLevel))
+		gomega.Expect(GetLogFile()).To(gomega.BeEmpty())
+		gomega.Expect(GetLogStderr()).To(gomega.BeFalse())
+		gomega.Expect(loggingW).To(gomega.BeNil())
+	})
+
+	It("Check variables configure logging", func() {
+		logFile := filepath.Join(GinkgoT().TempDir(), "multus.log")
+		GinkgoT().Setenv(EnvLogLevel, "Debug")
+		GinkgoT().Setenv(EnvLogFile, logFile)
+		GinkgoT().Setenv(EnvLogToStderr, "true")
+		GinkgoT().Setenv(EnvLogMaxSize, "10")
+		GinkgoT().Setenv(EnvLogCompress, "false")
+
+		gomega.Expect(ConfigureFromEnv()).To(gomega.Succeed())
+		gomega.Expect(GetLoggingLevel()).To(gomega.Equal(DebugLevel))
+		gomega.Expect(GetLogFile()).To(gomega.Equal(logFile))
+		gomega.Expect(GetLogStderr()).To(gomega.BeTrue())
+		gomega.Expect(GetLogOptions()).To(gomega.Equal(LogOptions{
+			MaxAge:       testutils.Int(5),
+			MaxSize:      testutils.Int(10),
+			MaxBackups:   testutils.Int(5),
+			Compress:     testutils.Bool(false),
+			MaxTotalSize: testutils.Int(0),
+		}))
+	})
+
+	It("Check invalid values return an error and configure nothing", func() {
+		GinkgoT().Setenv(EnvLogFile, filepath.Join(GinkgoT().TempDir(), "multus.log"))
+		GinkgoT().Setenv(EnvLogToStderr, "true")
+
+		GinkgoT().Setenv(EnvLogLevel, "chatty")
+		gomega.Expect(ConfigureFromEnv()).To(gomega.MatchError(`invalid MULTUS_LOG_LEVEL: unknown logging level "chatty", accepted levels are: trace, debug, verbose, warning, error, panic`))
+		GinkgoT().Setenv(EnvLogLevel, "")
+
+		GinkgoT().Setenv(EnvLogMaxAge, "a week")
+		gomega.Expect(ConfigureFromEnv()).To(gomega.MatchError(`invalid MULTUS_LOG_MAX_AGE "a week": must be an integer`))
+		GinkgoT().Setenv(EnvLogMaxAge, "-1")
+		gomega.Expect(ConfigureFromEnv()).To(gomega.MatchError("invalid log option maxAge: -1, must not be negative"))
+		GinkgoT().Setenv(EnvLogMaxAge, "")
+
+		GinkgoT().Setenv(EnvLogCompress, "gzip")
+		gomega.Expect(ConfigureFromEnv()).To(gomega.MatchError(`invalid MULTUS_LOG_COMPRESS "gzip": must be a boolean`))
+
+		gomega.Expect(GetLogFile()).To(gomega.BeEmpty())
+		gomega.Expect(GetLogStderr()).To(gomega.BeFalse())
+	})
+
+	It("Check all invalid values are reported before any is applied", func() {
+		logFile := filepath.Join(GinkgoT().TempDir(), "multus.log")
+		GinkgoT().Setenv(EnvLogFile, logFile)
+		GinkgoT().Setenv(EnvLogLevel, "Debug")
+		GinkgoT().Setenv(EnvLogToStderr, "yes please")
+		GinkgoT().Setenv(EnvLogMaxSize, "-1")
+
+		err := ConfigureFromEnv()
+		gomega.Expect(err).To(gomega.MatchError(gomega.ContainSubstring(`invalid MULTUS_LOG_TO_STDERR "yes please": must be a boolean`)))
+		gomega.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("invalid log option maxSize: -1, must not be negative")))
+		gomega.Expect(GetLoggingLevel()).To(gomega.Equal(PanicLevel))
+		gomega.Expect(GetLogFile()).To(gomega.BeEmpty())
+		gomega.Expect(logFile).NotTo(gomega.BeAnExistingFile())
+	})
+
+	It("Check an unwritable log file returns an error", func() {
+		GinkgoT().Setenv(EnvLogFile, filepath.Join(GinkgoT().TempDir(), "missing", "multus.log"))
+		gomega.Expect(ConfigureFromEnv()).To(gomega.MatchError(gomega.HavePrefix("invalid MULTUS_LOG_FILE: failed to open log file")))
+	})
+})
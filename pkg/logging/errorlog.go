@@ -0,0 +1,60 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+// SetErrorLogFile sets an error log file, which gets the error, panic and
+// fatal lines in addition to the log file. The error log file follows the
+// LogOptions of the log file. An empty filename stops writing the error log
+// file. It returns an error, and keeps the current error log file, when
+// filename cannot be opened for writing
+func SetErrorLogFile(filename string) error {
+	if filename == "" {
+		loggingMutex.Lock()
+		defer loggingMutex.Unlock()
+		if errorLogger == nil {
+			return nil
+		}
+		err := errorLogger.Close()
+		errorLogger = nil
+		errorTotalSize = nil
+		return err
+	}
+
+	loggingMutex.RLock()
+	mode := loggingFileMode
+	loggingMutex.RUnlock()
+	if err := checkWritable(filename, mode); err != nil {
+		return err
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	setErrorLogger(filename)
+	return nil
+}
+
+// setErrorLogger sets the error log file with the configuration of the log
+// file, closing the current one. The caller must hold loggingMutex
+func setErrorLogger(filename string) {
+	ensureLogger()
+	if errorLogger != nil {
+		errorLogger.Close()
+	}
+	errorLogger = copyLogger(logger)
+	errorLogger.Filename = filename
+	errorTotalSize = nil
+	if loggingTotalSize != nil {
+		errorTotalSize = newTotalSizeCap(errorLogger, loggingTotalSize.maxTotalSize, loggingTotalSize.zstd)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"os"
+	"path/filepath"
+
+	testutils "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging error log file", func() {
+	var tmpDir, logFile, errorLogFile string
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		tmpDir = GinkgoT().TempDir()
+		logFile = filepath.Join(tmpDir, "multus.log")
+		errorLogFile = filepath.Join(tmpDir, "multus-error.log")
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
+		SetLogLevel("verbose")
+		SetTimestamp(false)
+	})
+
+	AfterEach(func() {
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	It("Check errors are mirrored to the error log file", func() {
+		gomega.Expect(SetErrorLogFile(errorLogFile)).To(gomega.Succeed())
+		Verbosef("verbose line")
+		Warningf("warning line")
+		_ = Errorf("error line")
+
+		gomega.Expect(os.ReadFile(logFile)).To(gomega.BeEquivalentTo("[verbose] verbose line\n[warning] warning line\n[error] error line\n"))
+		gomega.Expect(os.ReadFile(errorLogFile)).To(gomega.BeEquivalentTo("[error] error line\n"))
+	})
+
+	It("Check the error log file follows the LogOptions", func() {
+		gomega.Expect(SetErrorLogFile(errorLogFile)).To(gomega.Succeed())
+		gomega.Expect(SetLogOptions(&LogOptions{MaxSize: testutils.Int(10), MaxBackups: testutils.Int(2)})).To(gomega.Succeed())
+		gomega.Expect(errorLogger.Filename).To(gomega.Equal(errorLogFile))
+		gomega.Expect(errorLogger.MaxSize).To(gomega.Equal(10))
+		gomega.Expect(errorLogger.MaxBackups).To(gomega.Equal(2))
+
+		SetUTC(true)
+		gomega.Expect(errorLogger.LocalTime).To(gomega.BeFalse())
+	})
+
+	It("Check an empty filename stops the error log file", func() {
+		gomega.Expect(SetErrorLogFile(errorLogFile)).To(gomega.Succeed())
+		gomega.Expect(SetErrorLogFile("")).To(gomega.Succeed())
+		_ = Errorf("error line")
+		gomega.Expect(os.ReadFile(errorLogFile)).To(gomega.BeEmpty())
+		gomega.Expect(os.ReadFile(logFile)).To(gomega.BeEquivalentTo("[error] error line\n"))
+	})
+
+	It("Check an unwritable error log file returns an error", func() {
+		gomega.Expect(SetErrorLogFile(filepath.Join(tmpDir, "missing", "multus-error.log"))).To(gomega.MatchError(gomega.HavePrefix("failed to open log file")))
+		gomega.Expect(errorLogger).To(gomega.BeNil())
+	})
+})
@@ -0,0 +1,69 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"strings"
+)
+
+// Filter is called with the level and the message of every log line before it
+// is written, and returns false to drop it
+type Filter func(level Level, msg string) bool
+
+// AddFilter registers fn to decide whether log lines are written, e.g. to
+// drop known benign warnings without lowering the logging level. Filters run
+// in registration order on the formatted message, after the logging level
+// filter and before deduplication, sampling and rate limiting. A line dropped
+// by a filter is not passed to the hooks. Panic and fatal lines are not
+// filtered, unless SetFilterPanic allows it for panic lines. Filters run
+// without the logging lock held, so they may log and configure logging
+func AddFilter(fn Filter) {
+	if fn == nil {
+		return
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingFilters = append(loggingFilters, fn)
+}
+
+// AddSubstringFilter drops the log lines whose message contains substr
+func AddSubstringFilter(substr string) {
+	AddFilter(func(_ Level, msg string) bool {
+		return !strings.Contains(msg, substr)
+	})
+}
+
+// SetFilterPanic sets flag for running the filters on panic lines too
+func SetFilterPanic(enable bool) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingFilterPanic = enable
+}
+
+// ClearFilters unregisters all the filters
+func ClearFilters() {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingFilters = nil
+}
+
+// levelFilters returns the filters run on the lines of level. The caller
+// must hold loggingMutex
+func levelFilters(level Level) []Filter {
+	if level == FatalLevel || level == PanicLevel && !loggingFilterPanic {
+		return nil
+	}
+	return loggingFilters
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging filters", func() {
+	var w *writeRecorder
+
+	BeforeEach(func() {
+		w = recordLogs(VerboseLevel)
+	})
+
+	It("Check filters drop the lines in registration order", func() {
+		var calls []string
+		AddFilter(func(level Level, msg string) bool {
+			calls = append(calls, "first "+msg)
+			return level != WarningLevel
+		})
+		AddFilter(func(level Level, msg string) bool {
+			calls = append(calls, "second "+msg)
+			return true
+		})
+		AddFilter(nil)
+		var hooked []string
+		RegisterHook(func(level Level, msg string) {
+			hooked = append(hooked, msg)
+		})
+
+		Warningf("benign %d", 1)
+		Verbosef("kept")
+		gomega.Expect(calls).To(gomega.Equal([]string{"first benign 1", "first kept", "second kept"}))
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[verbose] kept\n"}))
+		gomega.Expect(hooked).To(gomega.Equal([]string{"kept"}))
+
+		ClearFilters()
+		Warningf("benign %d", 2)
+		gomega.Expect(w.writes).To(gomega.HaveLen(2))
+	})
+
+	It("Check the substring filter", func() {
+		AddSubstringFilter("bridge already exists")
+		Warningf("delegate: bridge already exists, continuing")
+		Warningf("delegate: no such device")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[warning] delegate: no such device\n"}))
+	})
+
+	It("Check panic and fatal lines are not filtered unless allowed", func() {
+		exitFunc = func(int) {}
+		defer func() { exitFunc = os.Exit }()
+		AddFilter(func(Level, string) bool { return false })
+		SetPanicFormat(PanicFormat{NoBanners: true})
+
+		Panicf("crash")
+		Fatalf("exit")
+		gomega.Expect(w.writes).To(gomega.HaveLen(3))
+		gomega.Expect(w.writes[0]).To(gomega.Equal("[panic] crash\n"))
+		gomega.Expect(w.writes[2]).To(gomega.HavePrefix("[fatal] exit"))
+
+		SetFilterPanic(true)
+		Panicf("crash")
+		gomega.Expect(w.writes).To(gomega.HaveLen(3))
+	})
+
+	It("Check filters can log and configure logging", func() {
+		AddFilter(func(level Level, msg string) bool {
+			if msg == "configure" {
+				SetLogLevel("debug")
+				Debugf("filter saw %q", msg)
+			}
+			return true
+		})
+		Verbosef("configure")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[debug] filter saw \"configure\"\n", "[verbose] configure\n"}))
+	})
+
+	It("Check a line is not written when a filter disables it", func() {
+		AddFilter(func(level Level, msg string) bool {
+			loggingMutex.Lock()
+			loggingW = nil
+			loggingMutex.Unlock()
+			gomega.Expect(SetLogLevel("error")).To(gomega.Succeed())
+			return true
+		})
+		Verbosef("foobar")
+		gomega.Expect(w.writes).To(gomega.BeEmpty())
+		gomega.Expect(GetLoggingLevel()).To(gomega.Equal(ErrorLevel))
+	})
+})
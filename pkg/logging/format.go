@@ -0,0 +1,251 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// maxPooledBuffer is the capacity above which a line buffer is not returned
+// to the pool, so that a single huge line does not stay allocated
+const maxPooledBuffer = 64 << 10
+
+// bufferPool holds the buffers log lines are formatted into
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// getBuffer returns an empty buffer from the pool
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns buf to the pool. The caller must not use buf afterwards
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// appendTimestamp writes the formatted record time to buf
+func appendTimestamp(buf *bytes.Buffer, t time.Time) {
+	var ts [64]byte
+	buf.Write(t.AppendFormat(ts[:0], loggingTimestampFormat))
+}
+
+// record is a single log message
+type record struct {
+	time      time.Time
+	level     Level
+	component string
+	caller    string
+	msg       string
+	fields    map[string]interface{}
+}
+
+// sortedKeys returns the keys of the record fields in sorted order, so that
+// log lines are stable
+func (r *record) sortedKeys() []string {
+	keys := make([]string, 0, len(r.fields))
+	for k := range r.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// truncatedField marks the log lines which fields were truncated
+const truncatedField = "_truncated"
+
+// loggedKeys returns the sorted keys of the fields to log, the first
+// loggingMaxFields of them, and whether some were dropped. The caller must
+// hold loggingMutex
+func (r *record) loggedKeys() ([]string, bool) {
+	keys := r.sortedKeys()
+	if loggingMaxFields > 0 && len(keys) > loggingMaxFields {
+		return keys[:loggingMaxFields], true
+	}
+	return keys, false
+}
+
+// formatText renders a whole log line into buf, so that it can be written at
+// once
+func formatText(buf *bytes.Buffer, r *record) {
+	if loggingTimestamp {
+		appendTimestamp(buf, r.time)
+		buf.WriteByte(' ')
+	}
+	buf.WriteByte('[')
+	buf.WriteString(levelText(r.level))
+	buf.WriteString("] ")
+	if r.component != "" {
+		buf.WriteByte('[')
+		buf.WriteString(r.component)
+		buf.WriteString("] ")
+	}
+	if r.caller != "" {
+		buf.WriteString(r.caller)
+		buf.WriteByte(' ')
+	}
+	appendMessage(buf, r.msg)
+	appendTextFields(buf, r)
+	if loggingHostname != "" {
+		buf.WriteString(" " + hostField + "=")
+		buf.WriteString(textValue(loggingHostname))
+	}
+	if loggingPID != 0 {
+		buf.WriteString(" " + pidField + "=")
+		buf.WriteString(strconv.Itoa(loggingPID))
+	}
+	buf.WriteByte('\n')
+}
+
+// appendTextFields appends the record fields to a text log line as
+// key=value pairs
+func appendTextFields(buf *bytes.Buffer, r *record) {
+	keys, truncated := r.loggedKeys()
+	for _, k := range keys {
+		if _, ok := r.fields[k].(errorChain); ok {
+			continue
+		}
+		buf.WriteByte(' ')
+		buf.WriteString(k)
+		buf.WriteByte('=')
+		buf.WriteString(textValue(r.fields[k]))
+	}
+	if truncated {
+		buf.WriteString(" " + truncatedField + "=true")
+	}
+}
+
+// textValue renders a field value, quoted when it would be ambiguous
+func textValue(v interface{}) string {
+	s := fmt.Sprint(v)
+	if s == "" || strings.ContainsAny(s, " =\"\t\r\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// jsonKeys are the keys of the json object which fields cannot override
+var jsonKeys = map[string]bool{
+	"time":      true,
+	"level":     true,
+	"component": true,
+	"caller":    true,
+	"msg":       true,
+	schemaField: true,
+}
+
+// formatJSON renders a whole log line into buf as a json object
+func formatJSON(buf *bytes.Buffer, r *record) {
+	buf.WriteByte('{')
+	if loggingTimestamp {
+		buf.WriteString(`"time":`)
+		var ts [64]byte
+		appendJSONString(buf, r.time.AppendFormat(ts[:0], loggingTimestampFormat))
+	}
+	appendJSONField(buf, "level", r.level.String())
+	if r.component != "" {
+		appendJSONField(buf, "component", r.component)
+	}
+	if r.caller != "" {
+		appendJSONField(buf, "caller", r.caller)
+	}
+	appendJSONField(buf, "msg", r.msg)
+	keys, truncated := r.loggedKeys()
+	for _, k := range keys {
+		key := k
+		if jsonKeys[k] || k == hostField && loggingHostname != "" || k == pidField && loggingPID != 0 {
+			key = "fields." + k
+		}
+		appendJSONField(buf, key, r.fields[k])
+	}
+	if truncated {
+		appendJSONField(buf, truncatedField, true)
+	}
+	if loggingHostname != "" {
+		appendJSONField(buf, hostField, loggingHostname)
+	}
+	if loggingPID != 0 {
+		appendJSONField(buf, pidField, loggingPID)
+	}
+	appendJSONField(buf, schemaField, loggingSchemaVersion)
+	buf.WriteString("}\n")
+}
+
+// appendJSONField appends "key":value to a json object being built in buf
+func appendJSONField(buf *bytes.Buffer, key string, value interface{}) {
+	if buf.Len() > 1 {
+		buf.WriteByte(',')
+	}
+	appendJSONValue(buf, key)
+	buf.WriteByte(':')
+	appendJSONValue(buf, value)
+}
+
+// appendJSONValue appends v in json, or its string form when v cannot be
+// marshaled
+func appendJSONValue(buf *bytes.Buffer, v interface{}) {
+	if s, ok := v.(string); ok && jsonPlain(s) {
+		buf.WriteByte('"')
+		buf.WriteString(s)
+		buf.WriteByte('"')
+		return
+	}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		// a string is always encoded
+		_ = enc.Encode(fmt.Sprint(v))
+	}
+	// drop the newline added by Encode
+	buf.Truncate(buf.Len() - 1)
+}
+
+// appendJSONString appends b as a json string
+func appendJSONString(buf *bytes.Buffer, b []byte) {
+	if jsonPlain(string(b)) {
+		buf.WriteByte('"')
+		buf.Write(b)
+		buf.WriteByte('"')
+		return
+	}
+	appendJSONValue(buf, string(b))
+}
+
+// jsonPlain reports whether s is encoded in json as is between quotes, so
+// that the encoder can be skipped
+func jsonPlain(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < ' ' || c >= utf8.RuneSelf || c == '"' || c == '\\' {
+			return false
+		}
+	}
+	return true
+}
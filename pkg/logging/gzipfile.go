@@ -0,0 +1,145 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// gzipFlushInterval is how often the inline compressed log file is made a
+// valid gzip file
+const gzipFlushInterval = time.Second
+
+// gzipFile writes to a gzip compressed log file. The file is a series of gzip
+// members, each of them closed on finish, so that it is valid in between
+type gzipFile struct {
+	mu   sync.Mutex
+	file *os.File
+	zw   *gzip.Writer
+	// dirty is set when lines were written to the current gzip member
+	dirty bool
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+func openGzipFile(filename string, mode os.FileMode) (*gzipFile, error) {
+	perm := mode
+	if perm == 0 {
+		perm = 0644
+	}
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, perm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file %s: %w", filename, err)
+	}
+	g := &gzipFile{
+		file: f,
+		zw:   gzip.NewWriter(f),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go g.run()
+	return g, nil
+}
+
+// run finishes the gzip member every gzipFlushInterval until stopped
+func (g *gzipFile) run() {
+	defer close(g.done)
+	ticker := time.NewTicker(gzipFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			g.finish()
+		case <-g.stop:
+			return
+		}
+	}
+}
+
+func (g *gzipFile) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.dirty = true
+	return g.zw.Write(p)
+}
+
+// finish closes the current gzip member, if lines were written to it, so that
+// the file is a valid gzip file. The next Write starts a new member
+func (g *gzipFile) finish() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.dirty {
+		return nil
+	}
+	err := g.zw.Close()
+	g.zw.Reset(g.file)
+	g.dirty = false
+	return err
+}
+
+// Close finishes the gzip member and closes the file
+func (g *gzipFile) Close() error {
+	close(g.stop)
+	<-g.done
+	return errors.Join(g.finish(), g.file.Close())
+}
+
+// SetInlineCompression sets flag for compressing the log file set by
+// SetLogFile as it is written, to save space on chatty debug captures. The log
+// lines go to the file with the .gz suffix added, e.g. multus.log.gz, instead
+// of the log file, which is not rotated meanwhile. The file cannot be tailed
+// live: it is made a valid gzip file every second, and by Flush and Close.
+// SetLogFile disables it
+func SetInlineCompression(enable bool) error {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	if !enable {
+		return closeGzipFile()
+	}
+	if loggingGzip != nil {
+		return nil
+	}
+	ensureLogger()
+	if logger.Filename == "" || loggingW != io.Writer(logger) {
+		return errors.New("cannot compress the log file inline: log file is not set")
+	}
+	g, err := openGzipFile(logger.Filename+".gz", loggingFileMode)
+	if err != nil {
+		return err
+	}
+	loggingGzip = g
+	loggingW = g
+	return nil
+}
+
+// closeGzipFile closes the inline compressed log file, if any, and writes to
+// the log file again. The caller must hold loggingMutex
+func closeGzipFile() error {
+	if loggingGzip == nil {
+		return nil
+	}
+	err := loggingGzip.Close()
+	if loggingW == io.Writer(loggingGzip) {
+		loggingW = logger
+	}
+	loggingGzip = nil
+	return err
+}
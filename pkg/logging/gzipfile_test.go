@@ -0,0 +1,105 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging inline compression", func() {
+	var logFile string
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		SetTimestamp(false)
+		logFile = filepath.Join(GinkgoT().TempDir(), "multus.log")
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
+		SetLogLevel("verbose")
+	})
+
+	AfterEach(func() {
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	// gunzip returns the decompressed content of the gzip file
+	gunzip := func(filename string) string {
+		f, err := os.Open(filename)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		defer f.Close()
+		zr, err := gzip.NewReader(f)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		data, err := io.ReadAll(zr)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		return string(data)
+	}
+
+	It("Check the log lines are compressed in a valid gzip file", func() {
+		gomega.Expect(SetInlineCompression(true)).To(gomega.Succeed())
+		Verbosef("first")
+		gomega.Expect(Flush()).To(gomega.Succeed())
+		gomega.Expect(gunzip(logFile + ".gz")).To(gomega.Equal("[verbose] first\n"))
+
+		Verbosef("second")
+		gomega.Expect(Flush()).To(gomega.Succeed())
+		gomega.Expect(Flush()).To(gomega.Succeed())
+		Verbosef("third")
+		gomega.Expect(Close()).To(gomega.Succeed())
+		gomega.Expect(gunzip(logFile + ".gz")).To(gomega.Equal("[verbose] first\n[verbose] second\n[verbose] third\n"))
+	})
+
+	It("Check a fatal line is readable before exiting", func() {
+		exitCode := -1
+		exitFunc = func(code int) {
+			exitCode = code
+			gomega.Expect(gunzip(logFile + ".gz")).To(gomega.Equal("[verbose] first\n[fatal] fatal\n"))
+		}
+		defer func() { exitFunc = os.Exit }()
+
+		gomega.Expect(SetInlineCompression(true)).To(gomega.Succeed())
+		Verbosef("first")
+		Fatalf("fatal")
+		gomega.Expect(exitCode).To(gomega.Equal(1))
+
+		exitFunc = func(int) {
+			gomega.Expect(gunzip(logFile + ".gz")).To(gomega.HaveSuffix("[fatal] [cache] fatal\n"))
+		}
+		Named("cache").Fatalf("fatal")
+	})
+
+	It("Check disabling it writes to the log file again", func() {
+		gomega.Expect(SetInlineCompression(true)).To(gomega.Succeed())
+		gomega.Expect(SetInlineCompression(true)).To(gomega.Succeed())
+		Verbosef("compressed")
+		gomega.Expect(SetInlineCompression(false)).To(gomega.Succeed())
+		Verbosef("plain")
+		gomega.Expect(gunzip(logFile + ".gz")).To(gomega.Equal("[verbose] compressed\n"))
+		data, err := os.ReadFile(logFile)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(string(data)).To(gomega.Equal("[verbose] plain\n"))
+	})
+
+	It("Check it needs the log file", func() {
+		Reset()
+		gomega.Expect(SetInlineCompression(true)).To(gomega.MatchError("cannot compress the log file inline: log file is not set"))
+		gomega.Expect(loggingGzip).To(gomega.BeNil())
+	})
+})
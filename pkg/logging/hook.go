@@ -0,0 +1,78 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import "time"
+
+// Hook is called with the level and the message of every log line written
+type Hook func(level Level, msg string)
+
+// hookCall is a call of the hooks and of the record sink with a log line
+type hookCall struct {
+	hooks []Hook
+	level Level
+	msg   string
+	sink  RecordSink
+	rec   Record
+}
+
+// newHookCall returns the call of the registered hooks and of the record sink
+// with the log line of e created at t, the message redacted as the log line.
+// The caller must hold loggingMutex
+func newHookCall(e *LogEntry, level Level, t time.Time, msg string) hookCall {
+	if len(loggingHooks) == 0 && loggingRecordSink == nil {
+		return hookCall{}
+	}
+	if len(loggingRedactors) > 0 {
+		msg = string(redact([]byte(msg)))
+	}
+	c := hookCall{hooks: loggingHooks, level: level, msg: msg}
+	if loggingRecordSink != nil {
+		c.sink = loggingRecordSink
+		c.rec = newRecord(e, level, t, msg)
+	}
+	return c
+}
+
+// run calls the hooks in registration order, then the record sink. It must
+// be called without loggingMutex, so that they can log and configure logging
+func (c hookCall) run() {
+	for _, h := range c.hooks {
+		h(c.level, c.msg)
+	}
+	if c.sink != nil {
+		c.sink(c.rec)
+	}
+}
+
+// RegisterHook registers fn to be called with the level and the message of
+// every log line written, after the logging level filter, deduplication and
+// rate limiting. Hooks run in registration order, in the goroutine logging
+// the line once it is written, and may log themselves
+func RegisterHook(fn Hook) {
+	if fn == nil {
+		return
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingHooks = append(loggingHooks, fn)
+}
+
+// ClearHooks unregisters all the hooks
+func ClearHooks() {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingHooks = nil
+}
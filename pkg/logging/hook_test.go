@@ -0,0 +1,68 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging hooks", func() {
+	var w *writeRecorder
+
+	BeforeEach(func() {
+		w = recordLogs(VerboseLevel)
+	})
+
+	It("Check hooks run in registration order for written lines", func() {
+		var calls []string
+		RegisterHook(func(level Level, msg string) {
+			calls = append(calls, "first "+level.String()+" "+msg)
+		})
+		RegisterHook(func(level Level, msg string) {
+			calls = append(calls, "second "+level.String()+" "+msg)
+		})
+		RegisterHook(nil)
+
+		Debugf("filtered")
+		_ = Errorf("failed %d", 1)
+		gomega.Expect(calls).To(gomega.Equal([]string{"first error failed 1", "second error failed 1"}))
+
+		ClearHooks()
+		Verbosef("no hooks")
+		gomega.Expect(calls).To(gomega.HaveLen(2))
+	})
+
+	It("Check hooks can log and configure logging", func() {
+		RegisterHook(func(level Level, msg string) {
+			if level == ErrorLevel {
+				SetLogLevel("debug")
+				Debugf("hook saw %q", msg)
+			}
+		})
+		_ = Errorf("failed")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[error] failed\n", "[debug] hook saw \"failed\"\n"}))
+	})
+
+	It("Check hooks get the redacted message", func() {
+		AddKeyRedactor("password")
+		var got string
+		RegisterHook(func(level Level, msg string) {
+			got = msg
+		})
+		Verbosef(`config {"password": "hunter2"}`)
+		gomega.Expect(got).To(gomega.Equal(`config {"password": "***"}`))
+	})
+})
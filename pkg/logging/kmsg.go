@@ -0,0 +1,78 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// kmsgPath is the kernel message buffer, tests replace it
+var kmsgPath = "/dev/kmsg"
+
+// kmsgMaxLine is the longest record the kernel accepts from /dev/kmsg,
+// longer lines are truncated
+const kmsgMaxLine = 992
+
+// SetKmsgOutput sets flag for writing the error, panic and fatal lines to the
+// kernel message buffer, so that they show up in dmesg and crash dumps on
+// nodes without persistent storage nor syslog. The lines of the other levels
+// are not written there, not to flood it. It returns an error, and keeps the
+// current setting, when /dev/kmsg cannot be opened for writing
+func SetKmsgOutput(enable bool) error {
+	if !enable {
+		loggingMutex.Lock()
+		defer loggingMutex.Unlock()
+		return closeKmsg()
+	}
+
+	f, err := os.OpenFile(kmsgPath, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open the kernel message buffer: %w", err)
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	closeKmsg()
+	loggingKmsg = f
+	return nil
+}
+
+// closeKmsg closes the kernel message buffer, if open, and stops writing log
+// lines to it. The caller must hold loggingMutex
+func closeKmsg() error {
+	if loggingKmsg == nil {
+		return nil
+	}
+	err := loggingKmsg.Close()
+	loggingKmsg = nil
+	return err
+}
+
+// writeKmsg writes the line of a severe level to the kernel message buffer
+// w as a single record, prefixed with the <priority> of the level
+func writeKmsg(w io.Writer, level Level, line []byte) error {
+	record := make([]byte, 0, len(line)+4)
+	record = append(record, '<')
+	record = strconv.AppendInt(record, int64(syslogSeverity(level)), 10)
+	record = append(record, '>')
+	record = append(record, line...)
+	if len(record) > kmsgMaxLine {
+		record = append(record[:kmsgMaxLine-1], '\n')
+	}
+	_, err := w.Write(record)
+	return err
+}
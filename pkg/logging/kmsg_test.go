@@ -0,0 +1,75 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging kernel message buffer", func() {
+	var kmsgFile string
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		SetTimestamp(false)
+		SetLogLevel("debug")
+		kmsgFile = filepath.Join(GinkgoT().TempDir(), "kmsg")
+		gomega.Expect(os.WriteFile(kmsgFile, nil, 0600)).To(gomega.Succeed())
+		kmsgPath = kmsgFile
+	})
+
+	AfterEach(func() {
+		kmsgPath = "/dev/kmsg"
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	It("Check only the severe lines are written with their priority", func() {
+		gomega.Expect(SetKmsgOutput(true)).To(gomega.Succeed())
+		Debugf("debug")
+		Warningf("warning")
+		_ = Errorf("error")
+		Named("cache").Panicf("panic")
+		gomega.Expect(SetKmsgOutput(false)).To(gomega.Succeed())
+		_ = Errorf("after disabling")
+
+		out, err := os.ReadFile(kmsgFile)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(string(out)).To(gomega.HavePrefix("<3>[error] error\n<2>[panic] [cache] panic\n"))
+		gomega.Expect(string(out)).NotTo(gomega.ContainSubstring("after disabling"))
+	})
+
+	It("Check long lines are truncated", func() {
+		gomega.Expect(SetKmsgOutput(true)).To(gomega.Succeed())
+		_ = Errorf("%s", strings.Repeat("x", 2*kmsgMaxLine))
+		out, err := os.ReadFile(kmsgFile)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(out).To(gomega.HaveLen(kmsgMaxLine))
+		gomega.Expect(string(out)).To(gomega.HavePrefix("<3>[error] xxx"))
+		gomega.Expect(string(out)).To(gomega.HaveSuffix("x\n"))
+	})
+
+	It("Check an unwritable kernel message buffer returns an error", func() {
+		kmsgPath = filepath.Join(filepath.Dir(kmsgFile), "missing", "kmsg")
+		gomega.Expect(SetKmsgOutput(true)).To(gomega.MatchError(gomega.ContainSubstring("failed to open the kernel message buffer")))
+		gomega.Expect(loggingKmsg).To(gomega.BeNil())
+		gomega.Expect(SetKmsgOutput(false)).To(gomega.Succeed())
+	})
+})
@@ -0,0 +1,85 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"errors"
+)
+
+// withKV returns a copy of e, or a new LogEntry when e is nil, with the
+// alternating keys and values added as fields
+func withKV(e *LogEntry, kv []interface{}) *LogEntry {
+	if e == nil {
+		e = &LogEntry{}
+	}
+	return e.WithFields(kvFields(kv))
+}
+
+// Tracekv prints msg followed by the alternating keys and values of kv as
+// fields, as in msg key=value key=value, if logging level >= trace. A key
+// without value is logged under the !BADKV key
+func Tracekv(msg string, kv ...interface{}) {
+	printf(withKV(nil, kv), TraceLevel, "%s", msg)
+}
+
+// Debugkv prints msg and the fields kv, as Tracekv, if logging level >= debug
+func Debugkv(msg string, kv ...interface{}) {
+	printf(withKV(nil, kv), DebugLevel, "%s", msg)
+}
+
+// Verbosekv prints msg and the fields kv, as Tracekv, if logging level >=
+// verbose
+func Verbosekv(msg string, kv ...interface{}) {
+	printf(withKV(nil, kv), VerboseLevel, "%s", msg)
+}
+
+// Warningkv prints msg and the fields kv, as Tracekv, if logging level >=
+// warning
+func Warningkv(msg string, kv ...interface{}) {
+	printf(withKV(nil, kv), WarningLevel, "%s", msg)
+}
+
+// Errorkv prints msg and the fields kv, as Tracekv, if logging level >=
+// error, and returns an error with msg as its message
+func Errorkv(msg string, kv ...interface{}) error {
+	printf(withKV(nil, kv), ErrorLevel, "%s", msg)
+	return errors.New(msg)
+}
+
+// Tracekv prints msg and the fields kv, as the package level Tracekv
+func (e *LogEntry) Tracekv(msg string, kv ...interface{}) {
+	printf(withKV(e, kv), TraceLevel, "%s", msg)
+}
+
+// Debugkv prints msg and the fields kv, as the package level Debugkv
+func (e *LogEntry) Debugkv(msg string, kv ...interface{}) {
+	printf(withKV(e, kv), DebugLevel, "%s", msg)
+}
+
+// Verbosekv prints msg and the fields kv, as the package level Verbosekv
+func (e *LogEntry) Verbosekv(msg string, kv ...interface{}) {
+	printf(withKV(e, kv), VerboseLevel, "%s", msg)
+}
+
+// Warningkv prints msg and the fields kv, as the package level Warningkv
+func (e *LogEntry) Warningkv(msg string, kv ...interface{}) {
+	printf(withKV(e, kv), WarningLevel, "%s", msg)
+}
+
+// Errorkv prints msg and the fields kv, as the package level Errorkv
+func (e *LogEntry) Errorkv(msg string, kv ...interface{}) error {
+	printf(withKV(e, kv), ErrorLevel, "%s", msg)
+	return errors.New(msg)
+}
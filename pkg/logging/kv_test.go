@@ -0,0 +1,56 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging key/value shortcuts", func() {
+
+	var w *writeRecorder
+
+	BeforeEach(func() {
+		w = recordLogs(DebugLevel)
+		SetLogCaller(true)
+	})
+
+	It("Check the keys and values are logged as fields", func() {
+		Debugkv("attaching", "ifname", "net1", "mtu", 1500)
+		gomega.Expect(Errorkv("failed 100%", "pod", "default/web-0")).To(gomega.MatchError("failed 100%"))
+		Named("cache").Warningkv("evicted", "key", "a b")
+		Tracekv("hidden", "k", "v")
+		gomega.Expect(w.writes).To(gomega.HaveLen(3))
+		gomega.Expect(w.writes[0]).To(gomega.MatchRegexp(`^\[debug\] kv_test\.go:\d+ attaching ifname=net1 mtu=1500\n$`))
+		gomega.Expect(w.writes[1]).To(gomega.MatchRegexp(`^\[error\] kv_test\.go:\d+ failed 100% pod=default/web-0\n$`))
+		gomega.Expect(w.writes[2]).To(gomega.MatchRegexp(`^\[warning\] \[cache\] kv_test\.go:\d+ evicted key="a b"\n$`))
+	})
+
+	It("Check an odd number of keys and values is marked", func() {
+		Verbosekv("attaching", "ifname", "net1", "dangling")
+		gomega.Expect(w.writes[0]).To(gomega.HaveSuffix(" attaching !BADKV=dangling ifname=net1\n"))
+
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		WithField("pod", "default/web-0").Debugkv("attaching", "ifname", "net1", "mtu", 1500)
+		record := map[string]interface{}{}
+		gomega.Expect(json.Unmarshal([]byte(w.writes[1]), &record)).To(gomega.Succeed())
+		gomega.Expect(record).To(gomega.HaveKeyWithValue("ifname", "net1"))
+		gomega.Expect(record).To(gomega.HaveKeyWithValue("mtu", 1500.0))
+		gomega.Expect(record).To(gomega.HaveKeyWithValue("pod", "default/web-0"))
+	})
+})
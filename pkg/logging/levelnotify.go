@@ -0,0 +1,56 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+// levelSubscribers are the channels of SubscribeLevelChanges, guarded by
+// loggingMutex
+var levelSubscribers = map[chan Level]struct{}{}
+
+// SubscribeLevelChanges returns a channel receiving the new logging level
+// whenever it changes, e.g. by SetLogLevel, SetLogLevelNum, WithLevel or a
+// signal, and a function to unsubscribe, which closes the channel. The sends
+// do not block: when the subscriber has not received the previous level yet,
+// it is dropped for the new one, so that a slow subscriber does not stall the
+// setters and still gets the current level
+func SubscribeLevelChanges() (<-chan Level, func()) {
+	ch := make(chan Level, 1)
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	levelSubscribers[ch] = struct{}{}
+	return ch, func() {
+		loggingMutex.Lock()
+		defer loggingMutex.Unlock()
+		if _, ok := levelSubscribers[ch]; ok {
+			delete(levelSubscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// setLevel sets the logging level and notifies the subscribers when it
+// changes. The caller must hold loggingMutex
+func setLevel(level Level) {
+	if level == loggingLevel.Load() {
+		return
+	}
+	loggingLevel.Store(level)
+	for ch := range levelSubscribers {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- level
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging level change notifications", func() {
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+	})
+
+	It("Check subscribers get the new levels until they unsubscribe", func() {
+		levels, unsubscribe := SubscribeLevelChanges()
+		other, unsubscribeOther := SubscribeLevelChanges()
+		defer unsubscribeOther()
+
+		gomega.Expect(SetLogLevel("debug")).To(gomega.Succeed())
+		gomega.Expect(levels).To(gomega.Receive(gomega.Equal(DebugLevel)))
+		gomega.Expect(other).To(gomega.Receive(gomega.Equal(DebugLevel)))
+
+		// unchanged levels are not sent
+		gomega.Expect(SetLogLevel("debug")).To(gomega.Succeed())
+		gomega.Expect(levels).NotTo(gomega.Receive())
+
+		SetLogLevelNum(1)
+		gomega.Expect(levels).To(gomega.Receive(gomega.Equal(ErrorLevel)))
+		WithLevel(TraceLevel)()
+		gomega.Expect(levels).To(gomega.Receive(gomega.Equal(ErrorLevel)))
+		gomega.Expect(levels).To(gomega.BeEmpty())
+
+		unsubscribe()
+		unsubscribe()
+		gomega.Expect(SetLogLevel("verbose")).To(gomega.Succeed())
+		gomega.Expect(levels).To(gomega.BeClosed())
+	})
+
+	It("Check a slow subscriber does not block the setters", func() {
+		levels, unsubscribe := SubscribeLevelChanges()
+		defer unsubscribe()
+		gomega.Expect(SetLogLevel("debug")).To(gomega.Succeed())
+		gomega.Expect(SetLogLevel("error")).To(gomega.Succeed())
+		gomega.Expect(SetLogLevel("verbose")).To(gomega.Succeed())
+		gomega.Expect(levels).To(gomega.Receive(gomega.Equal(VerboseLevel)))
+		gomega.Expect(levels).NotTo(gomega.Receive())
+	})
+})
@@ -0,0 +1,79 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// levelStyle is the rendering of the level in text log lines
+type levelStyle int
+
+const (
+	levelStyleFull levelStyle = iota
+	levelStyleShort
+	levelStyleNumeric
+)
+
+// shortLevels are the levels of the short style
+var shortLevels = map[Level]string{
+	PanicLevel:   "P",
+	ErrorLevel:   "E",
+	WarningLevel: "W",
+	VerboseLevel: "V",
+	DebugLevel:   "D",
+	TraceLevel:   "T",
+	FatalLevel:   "F",
+}
+
+// SetLevelStyle sets how the level is rendered in text log lines: "full"
+// as [debug], "short" as a single letter such as [D], or "numeric" as the
+// value of the level such as [4]. Info lines are verbose ones, [V] or [3].
+// Json log lines always have the full level name
+func SetLevelStyle(style string) error {
+	var s levelStyle
+	switch strings.ToLower(style) {
+	case "full":
+		s = levelStyleFull
+	case "short":
+		s = levelStyleShort
+	case "numeric":
+		s = levelStyleNumeric
+	default:
+		return fmt.Errorf("unknown level style %q, accepted styles are: full, short, numeric", style)
+	}
+
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingLevelStyle = s
+	return nil
+}
+
+// levelText returns the level as rendered in text log lines. The caller must
+// hold loggingMutex
+func levelText(l Level) string {
+	switch loggingLevelStyle {
+	case levelStyleShort:
+		if s, ok := shortLevels[l]; ok {
+			return s
+		}
+		return "?"
+	case levelStyleNumeric:
+		return strconv.Itoa(int(l))
+	}
+	return l.String()
+}
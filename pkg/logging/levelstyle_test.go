@@ -0,0 +1,70 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging level style", func() {
+	var c *Capture
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		SetTimestamp(false)
+		SetLogLevel("trace")
+		c = CaptureLogs()
+	})
+
+	AfterEach(func() {
+		c.Close()
+	})
+
+	logAll := func() {
+		Tracef("trace")
+		Debugf("debug")
+		Infof("info")
+		Warningf("warning")
+		_ = Errorf("error")
+		Logf(PanicLevel, "panic")
+	}
+
+	It("Check the short style", func() {
+		gomega.Expect(SetLevelStyle("SHORT")).To(gomega.Succeed())
+		logAll()
+		gomega.Expect(c.Lines()).To(gomega.Equal([]string{"[T] trace", "[D] debug", "[V] info", "[W] warning", "[E] error", "[P] panic"}))
+	})
+
+	It("Check the numeric style", func() {
+		gomega.Expect(SetLevelStyle("numeric")).To(gomega.Succeed())
+		logAll()
+		gomega.Expect(c.Lines()).To(gomega.Equal([]string{"[5] trace", "[4] debug", "[3] info", "[2] warning", "[1] error", "[0] panic"}))
+	})
+
+	It("Check the full style and invalid styles", func() {
+		gomega.Expect(SetLevelStyle("short")).To(gomega.Succeed())
+		gomega.Expect(SetLevelStyle("tiny")).To(gomega.MatchError(gomega.ContainSubstring("unknown level style")))
+		gomega.Expect(SetLevelStyle("full")).To(gomega.Succeed())
+		Named("cache").Debugf("debug")
+		gomega.Expect(c.Lines()).To(gomega.Equal([]string{"[debug] [cache] debug"}))
+
+		gomega.Expect(SetLevelStyle("short")).To(gomega.Succeed())
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		Debugf("debug")
+		gomega.Expect(c.Lines()[1]).To(gomega.Equal(`{"level":"debug","msg":"debug","schemaVersion":"1"}`))
+	})
+})
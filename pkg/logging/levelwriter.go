@@ -0,0 +1,98 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxPartialLine is the size over which a line written to a levelWriter is
+// logged without waiting for its newline, so that the writer does not buffer
+// output without newlines unbounded
+const maxPartialLine = 64 * 1024
+
+// levelWriter logs the lines written to it at a level
+type levelWriter struct {
+	mu    sync.Mutex
+	level Level
+	// partial is the last line written, until its newline is
+	partial []byte
+}
+
+// LevelWriter returns a writer logging each line written to it at level,
+// e.g. to log the output of a command:
+//
+//	stderr := logging.LevelWriter(logging.ErrorLevel)
+//	defer stderr.Close()
+//	cmd.Stderr = stderr
+//
+// Lines are logged once their newline is written, with no format expansion
+// and without the newline, and Close logs the last line when it has none.
+// Lines longer than 64 KiB are logged in parts of up to 64 KiB.
+// Empty lines are not logged. Each line is logged at once, so that it does
+// not interleave with the other log lines. Invalid levels log nothing
+func LevelWriter(level Level) io.WriteCloser {
+	return &levelWriter{level: level}
+}
+
+func (w *levelWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i >= 0 {
+			w.log(w.partial[:i])
+			w.partial = w.partial[i+1:]
+			continue
+		}
+		if len(w.partial) <= maxPartialLine {
+			break
+		}
+		// split the line between runes
+		i = maxPartialLine
+		for j := i; j > maxPartialLine-utf8.UTFMax; j-- {
+			if utf8.RuneStart(w.partial[j]) {
+				i = j
+				break
+			}
+		}
+		w.log(w.partial[:i])
+		w.partial = w.partial[i:]
+	}
+	return len(p), nil
+}
+
+// Close logs the last line, when it has no newline
+func (w *levelWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.log(w.partial)
+	w.partial = nil
+	return nil
+}
+
+// log logs line, without its carriage return if any. The caller must hold
+// w.mu
+func (w *levelWriter) log(line []byte) {
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	if len(line) == 0 || !w.level.valid() {
+		return
+	}
+	printf(nil, w.level, "%s", line)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging level writer", func() {
+	var c *Capture
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		SetTimestamp(false)
+		SetLogLevel("verbose")
+		c = CaptureLogs()
+	})
+
+	AfterEach(func() {
+		c.Close()
+	})
+
+	It("Check the lines are logged at the level", func() {
+		w := LevelWriter(ErrorLevel)
+		fmt.Fprint(w, "first ")
+		fmt.Fprint(w, "line\r\nsecond line\n\n100% third")
+		gomega.Expect(c.Lines()).To(gomega.Equal([]string{"[error] first line", "[error] second line"}))
+		gomega.Expect(w.Close()).To(gomega.Succeed())
+		gomega.Expect(c.Lines()).To(gomega.Equal([]string{"[error] first line", "[error] second line", "[error] 100% third"}))
+
+		debug := LevelWriter(DebugLevel)
+		fmt.Fprintln(debug, "filtered")
+		invalid := LevelWriter(UnknownLevel)
+		fmt.Fprintln(invalid, "invalid")
+		gomega.Expect(c.Lines()).To(gomega.HaveLen(3))
+	})
+
+	It("Check a line without newline is logged once it exceeds the cap", func() {
+		w := LevelWriter(ErrorLevel)
+		long := strings.Repeat("x", maxPartialLine-1) + "é"
+		fmt.Fprint(w, long+"tail")
+		gomega.Expect(c.Lines()).To(gomega.Equal([]string{"[error] " + long[:maxPartialLine-1]}))
+		gomega.Expect(w.Close()).To(gomega.Succeed())
+		gomega.Expect(c.Lines()).To(gomega.Equal([]string{"[error] " + long[:maxPartialLine-1], "[error] étail"}))
+	})
+
+	It("Check a line of exactly the cap waits for its newline", func() {
+		w := LevelWriter(ErrorLevel)
+		full := strings.Repeat("a", maxPartialLine)
+		fmt.Fprint(w, full)
+		gomega.Expect(c.Lines()).To(gomega.BeEmpty())
+		gomega.Expect(w.Close()).To(gomega.Succeed())
+		gomega.Expect(c.Lines()).To(gomega.Equal([]string{"[error] " + full}))
+	})
+
+	It("Check a line of one byte over the cap is logged in two parts", func() {
+		w := LevelWriter(ErrorLevel)
+		full := strings.Repeat("a", maxPartialLine)
+		fmt.Fprint(w, full+"b")
+		gomega.Expect(c.Lines()).To(gomega.Equal([]string{"[error] " + full}))
+		fmt.Fprintln(w, "c")
+		gomega.Expect(c.Lines()).To(gomega.Equal([]string{"[error] " + full, "[error] bc"}))
+	})
+
+	It("Check the output of a command is logged", func() {
+		stdout := LevelWriter(VerboseLevel)
+		cmd := exec.Command("sh", "-c", "echo one; printf two")
+		cmd.Stdout = stdout
+		gomega.Expect(cmd.Run()).To(gomega.Succeed())
+		gomega.Expect(stdout.Close()).To(gomega.Succeed())
+		gomega.Expect(c.Lines()).To(gomega.Equal([]string{"[verbose] one", "[verbose] two"}))
+	})
+
+	It("Check lines written concurrently do not interleave", func() {
+		w := LevelWriter(VerboseLevel)
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					fmt.Fprintf(w, "writer line %d\n", i)
+					Verbosef("direct line %d", i)
+				}
+			}(i)
+		}
+		wg.Wait()
+		gomega.Expect(c.Lines()).To(gomega.HaveLen(200))
+		for _, line := range c.Lines() {
+			gomega.Expect(line).To(gomega.MatchRegexp(`^\[verbose\] (writer|direct) line \d$`))
+		}
+	})
+})
@@ -0,0 +1,95 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	lumberjack "gopkg.in/natefinch/lumberjack.v2"
+)
+
+// logDirCheckInterval is how often logDirRecovery checks that the log file
+// still exists, and its first backoff when it cannot recreate the directory
+const logDirCheckInterval = time.Second
+
+// maxLogDirBackoff is the longest backoff of logDirRecovery
+const maxLogDirBackoff = time.Minute
+
+// logDirRecovery reopens the log file when it is gone, e.g. when its
+// directory was on a volume which got unmounted
+type logDirRecovery struct {
+	mu sync.Mutex
+	// next is the unix time in nanoseconds of the next check
+	next    atomic.Int64
+	backoff time.Duration
+}
+
+// SetRecreateLogDir sets flag for recreating the directory of the log file,
+// and reopening the log file, when the log file is gone. Writing to a removed
+// file may not fail, so its existence is checked at most every second, on
+// write errors too, backing off up to a minute while the directory cannot be
+// recreated. The failures go to the write error handler
+func SetRecreateLogDir(enable bool) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	if !enable {
+		loggingLogDir = nil
+	} else if loggingLogDir == nil {
+		loggingLogDir = &logDirRecovery{}
+	}
+}
+
+// recover reopens the log file l when it is gone, unless it was checked less
+// than a backoff ago, and reports whether it did. werr is the error writing
+// the last line to l, if any
+func (r *logDirRecovery) recover(l *lumberjack.Logger, werr error) (bool, error) {
+	now := time.Now()
+	if now.UnixNano() < r.next.Load() || l.Filename == "" {
+		return false, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if now.UnixNano() < r.next.Load() {
+		return false, nil
+	}
+	r.next.Store(now.Add(logDirCheckInterval).UnixNano())
+	if _, err := os.Stat(l.Filename); !os.IsNotExist(err) {
+		return false, nil
+	}
+
+	dir := filepath.Dir(l.Filename)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		r.backoff *= 2
+		if r.backoff == 0 {
+			r.backoff = logDirCheckInterval
+		} else if r.backoff > maxLogDirBackoff {
+			r.backoff = maxLogDirBackoff
+		}
+		r.next.Store(now.Add(r.backoff).UnixNano())
+		return false, fmt.Errorf("failed to recreate the log directory %s: %w", dir, err)
+	}
+	r.backoff = 0
+	// lumberjack opens the log file again on the next write
+	if err := l.Close(); err != nil && werr == nil {
+		return true, fmt.Errorf("failed to close the removed log file: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging directory recreation", func() {
+	var logDir string
+	var logFile string
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		logDir = filepath.Join(GinkgoT().TempDir(), "logs")
+		logFile = filepath.Join(logDir, "multus.log")
+		gomega.Expect(os.Mkdir(logDir, 0755)).To(gomega.Succeed())
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
+		SetLogLevel("verbose")
+	})
+
+	AfterEach(func() {
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	It("Check the log file is reopened when its directory is removed", func() {
+		SetRecreateLogDir(true)
+		Verbosef("before")
+		gomega.Expect(os.RemoveAll(logDir)).To(gomega.Succeed())
+
+		// the removal is noticed after the check interval
+		Verbosef("lost")
+		_, err := os.Stat(logFile)
+		gomega.Expect(os.IsNotExist(err)).To(gomega.BeTrue())
+		loggingLogDir.next.Store(0)
+		Verbosef("after")
+
+		data, err := os.ReadFile(logFile)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(string(data)).NotTo(gomega.ContainSubstring("before"))
+		gomega.Expect(string(data)).To(gomega.ContainSubstring("after"))
+	})
+
+	It("Check the log file is not reopened by default", func() {
+		Verbosef("before")
+		gomega.Expect(os.RemoveAll(logDir)).To(gomega.Succeed())
+		Verbosef("after")
+		_, err := os.Stat(logFile)
+		gomega.Expect(os.IsNotExist(err)).To(gomega.BeTrue())
+	})
+})
@@ -0,0 +1,93 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// LogFileInfo describes a log file or one of its backups
+type LogFileInfo struct {
+	// Name is the path of the file
+	Name    string
+	Size    int64
+	ModTime time.Time
+	// Compressed is set for the files compressed with gzip or zstd
+	Compressed bool
+}
+
+// ListLogFiles returns the log file and its backups, newest first: the log
+// file, then the backups by the time of their rotation, as told by their
+// names. Both the compressed and the uncompressed backups are listed. It
+// returns nothing when the log file is not set
+func ListLogFiles() ([]LogFileInfo, error) {
+	loggingMutex.RLock()
+	var filename string
+	var localTime bool
+	if logger != nil {
+		filename = logger.Filename
+		localTime = logger.LocalTime
+	}
+	inline := loggingGzip != nil
+	loggingMutex.RUnlock()
+	if filename == "" {
+		return nil, nil
+	}
+
+	var files []LogFileInfo
+	active := []string{filename}
+	if inline {
+		active = append([]string{filename + ".gz"}, active...)
+	}
+	for _, name := range active {
+		info, err := os.Stat(name)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the log files: %w", err)
+		}
+		files = append(files, logFileInfo(name, info))
+	}
+
+	backups, err := logBackups(filename, localTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the log files: %w", err)
+	}
+	for _, b := range backups {
+		info, err := os.Stat(b.path())
+		if os.IsNotExist(err) {
+			// removed or compressed in the meantime
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the log files: %w", err)
+		}
+		files = append(files, logFileInfo(b.path(), info))
+	}
+	return files, nil
+}
+
+func logFileInfo(name string, info os.FileInfo) LogFileInfo {
+	return LogFileInfo{
+		Name:       name,
+		Size:       info.Size(),
+		ModTime:    info.ModTime(),
+		Compressed: strings.HasSuffix(name, ".gz") || strings.HasSuffix(name, zstdSuffix),
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"os"
+	"path/filepath"
+
+	testutils "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging log files listing", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		tmpDir = GinkgoT().TempDir()
+		SetLogLevel("verbose")
+	})
+
+	AfterEach(func() {
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	It("Check the log file and its backups are listed newest first", func() {
+		logFile := filepath.Join(tmpDir, "multus.log")
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
+		Verbosef("foobar")
+		for name, size := range map[string]int{
+			"multus-2023-01-02T00-00-00.000.log":     2,
+			"multus-2023-01-01T00-00-00.000.log.gz":  1,
+			"multus-2023-01-03T00-00-00.000.log.zst": 3,
+			"multus-latest.log":                      1,
+			"other-2023-01-04T00-00-00.000.log":      1,
+		} {
+			gomega.Expect(os.WriteFile(filepath.Join(tmpDir, name), make([]byte, size), 0644)).To(gomega.Succeed())
+		}
+
+		files, err := ListLogFiles()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		var names []string
+		for _, f := range files {
+			names = append(names, filepath.Base(f.Name))
+		}
+		gomega.Expect(names).To(gomega.Equal([]string{
+			"multus.log",
+			"multus-2023-01-03T00-00-00.000.log.zst",
+			"multus-2023-01-02T00-00-00.000.log",
+			"multus-2023-01-01T00-00-00.000.log.gz",
+		}))
+		gomega.Expect(files[0].Name).To(gomega.Equal(logFile))
+		gomega.Expect(files[0].Size).To(gomega.BeNumerically(">", 0))
+		gomega.Expect(files[0].Compressed).To(gomega.BeFalse())
+		gomega.Expect(files[0].ModTime.IsZero()).To(gomega.BeFalse())
+		gomega.Expect(files[1].Size).To(gomega.Equal(int64(3)))
+		gomega.Expect(files[1].Compressed).To(gomega.BeTrue())
+		gomega.Expect(files[2].Compressed).To(gomega.BeFalse())
+		gomega.Expect(files[3].Compressed).To(gomega.BeTrue())
+	})
+
+	It("Check a rotated backup is listed", func() {
+		gomega.Expect(SetLogOptions(&LogOptions{Compress: testutils.Bool(false)})).To(gomega.Succeed())
+		gomega.Expect(SetLogFile(filepath.Join(tmpDir, "multus.log"))).To(gomega.Succeed())
+		Verbosef("before rotation")
+		gomega.Expect(Rotate()).To(gomega.Succeed())
+		files, err := ListLogFiles()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(files).To(gomega.HaveLen(2))
+		gomega.Expect(filepath.Base(files[1].Name)).To(gomega.MatchRegexp(`^multus-.*\.log$`))
+	})
+
+	It("Check nothing is listed without a log file", func() {
+		files, err := ListLogFiles()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(files).To(gomega.BeEmpty())
+	})
+})
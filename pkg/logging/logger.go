@@ -0,0 +1,45 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+// Logger logs messages at the logging levels. Code taking a Logger, rather
+// than calling the package functions, can be given a fake recording the
+// messages in tests. *LogEntry implements it
+type Logger interface {
+	Tracef(format string, a ...interface{})
+	Debugf(format string, a ...interface{})
+	Verbosef(format string, a ...interface{})
+	Infof(format string, a ...interface{})
+	Warningf(format string, a ...interface{})
+	Errorf(format string, a ...interface{}) error
+	Panicf(format string, a ...interface{})
+	Fatalf(format string, a ...interface{})
+}
+
+var _ Logger = (*LogEntry)(nil)
+
+// defaultLogger logs without annotations
+var defaultLogger = &LogEntry{}
+
+// packageLogger is defaultLogger for the package functions, which wrap its
+// methods and so are one frame further from the code logging
+var packageLogger = &LogEntry{callDepth: 1}
+
+// Default returns the Logger backed by the package configuration. The
+// package functions of the Logger methods, such as Debugf, are wrappers over
+// it
+func Default() Logger {
+	return defaultLogger
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+// fakeLogger records the messages logged through the Logger interface
+type fakeLogger struct {
+	messages []string
+}
+
+func (l *fakeLogger) record(level Level, format string, a ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf("[%s] ", level)+fmt.Sprintf(format, a...))
+}
+
+func (l *fakeLogger) Tracef(format string, a ...interface{}) { l.record(TraceLevel, format, a...) }
+
+func (l *fakeLogger) Debugf(format string, a ...interface{}) { l.record(DebugLevel, format, a...) }
+
+func (l *fakeLogger) Verbosef(format string, a ...interface{}) { l.record(VerboseLevel, format, a...) }
+
+func (l *fakeLogger) Infof(format string, a ...interface{}) { l.record(InfoLevel, format, a...) }
+
+func (l *fakeLogger) Warningf(format string, a ...interface{}) { l.record(WarningLevel, format, a...) }
+
+func (l *fakeLogger) Errorf(format string, a ...interface{}) error {
+	l.record(ErrorLevel, format, a...)
+	return fmt.Errorf(format, a...)
+}
+
+func (l *fakeLogger) Panicf(format string, a ...interface{}) { l.record(PanicLevel, format, a...) }
+
+func (l *fakeLogger) Fatalf(format string, a ...interface{}) { l.record(FatalLevel, format, a...) }
+
+// attach is code taking a Logger
+func attach(log Logger, ifname string) error {
+	log.Verbosef("attaching %s", ifname)
+	return log.Errorf("failed to attach %s", ifname)
+}
+
+var _ = Describe("logging Logger interface", func() {
+	var w *writeRecorder
+
+	BeforeEach(func() {
+		w = recordLogs(VerboseLevel)
+	})
+
+	AfterEach(func() {
+		Reset()
+	})
+
+	It("Check the default Logger logs as the package functions", func() {
+		gomega.Expect(attach(Default(), "net1")).To(gomega.MatchError("failed to attach net1"))
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[verbose] attaching net1\n", "[error] failed to attach net1\n"}))
+	})
+
+	It("Check the caller is the logging code through Default and the package functions", func() {
+		SetLogCaller(true)
+		Default().Verbosef("default")
+		Verbosef("package")
+		gomega.Expect(w.writes).To(gomega.HaveLen(2))
+		gomega.Expect(w.writes[0]).To(gomega.MatchRegexp(`^\[verbose\] logger_test\.go:\d+ default\n$`))
+		gomega.Expect(w.writes[1]).To(gomega.MatchRegexp(`^\[verbose\] logger_test\.go:\d+ package\n$`))
+	})
+
+	It("Check a fake Logger records the messages", func() {
+		log := &fakeLogger{}
+		gomega.Expect(attach(log, "net1")).To(gomega.HaveOccurred())
+		gomega.Expect(log.messages).To(gomega.Equal([]string{"[verbose] attaching net1", "[error] failed to attach net1"}))
+		gomega.Expect(w.writes).To(gomega.BeEmpty())
+	})
+})
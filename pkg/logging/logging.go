@@ -16,11 +16,18 @@
 package logging
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log/syslog"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	lumberjack "gopkg.in/natefinch/lumberjack.v2"
@@ -29,22 +36,116 @@ import (
 // Level type
 type Level uint32
 
-// PanicLevel...MaxLevel indicates the logging level
+// PanicLevel...MaxLevel indicates the logging level. The values are spelled
+// out, rather than taken from iota, so that adding a level is a visible change
+// of the numbering; levels should be persisted by name (see String).
+//
+// Adding WarningLevel renumbered VerboseLevel from 2 to 3 and DebugLevel from
+// 3 to 4, so that the levels stay ordered by verbosity. Code comparing a
+// Level with a number must use the constants instead. The numbers taken by
+// ParseLevel, UnmarshalJSON, SetLogLevelNum and V, and printed by the numeric
+// level style, are the values of the constants
+const (
+	PanicLevel   Level = 0
+	ErrorLevel   Level = 1
+	WarningLevel Level = 2
+	VerboseLevel Level = 3
+	DebugLevel   Level = 4
+	TraceLevel   Level = 5
+	MaxLevel     Level = 6
+	UnknownLevel Level = 7
+	// FatalLevel is not a logging level threshold: fatal messages are always
+	// logged, regardless of the current logging level
+	FatalLevel Level = 8
+	// InfoLevel is VerboseLevel, under the name used by klog
+	InfoLevel = VerboseLevel
+)
+
+// logFormat type
+type logFormat uint32
+
+// textFormat...jsonFormat indicates the format of log lines
 const (
-	PanicLevel Level = iota
-	ErrorLevel
-	VerboseLevel
-	DebugLevel
-	MaxLevel
-	UnknownLevel
+	textFormat logFormat = iota
+	jsonFormat
+	syslogFormat
 )
 
+// loggingMutex guards the package level logging configuration below
+var loggingMutex sync.RWMutex
+
 var loggingStderr bool
+var loggingStderrLevel Level
+var loggingStdout bool
 var loggingW io.Writer
-var loggingLevel Level
+var loggingOutputs []io.Writer
+var loggingLevel atomicLevel
+
+// loggingComponentMaxLevel is the highest level set by SetComponentLevel
+var loggingComponentMaxLevel atomicLevel
+var loggingFormat logFormat
+var loggingSchemaVersion string
+var loggingCaller bool
+var loggingHostname string
+var loggingPID int
+var loggingTimestamp bool
+var loggingTimestampFormat string
+var loggingUTC bool
+var panicStackSize int
+var panicFormat PanicFormat
+var loggingRateLimiter *rateLimiter
+var loggingRateLimitBypass bool
+var loggingDedup *deduplicator
+var loggingRedactors []redactor
+var loggingSyslog *syslog.Writer
+var loggingSocket *socketWriter
+var loggingSocketBufferSize int
+var loggingKmsg *os.File
+var loggingSyslogApp string
+var loggingSyslogFacility syslog.Priority
+var loggingAsync *asyncWriter
+var loggingTotalSize *totalSizeCap
+var loggingLogDir *logDirRecovery
+var loggingFileMode os.FileMode
+var loggingFileDefaults fileDefaults
+var loggingFileUsesDefaults bool
+
+// loggingOptionsSet is set once SetLogOptions is called, from then on
+// SetLogFile keeps the options of the log file
+var loggingOptionsSet bool
+var errorLogger *lumberjack.Logger
+var loggingColor colorMode
+var loggingHooks []Hook
+var loggingRecordSink RecordSink
+var loggingFilters []Filter
+var loggingFilterPanic bool
+var loggingRing *ringBuffer
+var loggingComponentLevels map[string]Level
+var loggingSamplers map[Level]*sampler
+var loggingTimeRotation *timeRotation
+var loggingDaily *dailyFile
+var loggingGzip *gzipFile
+var loggingCapture *Capture
+var loggingLevelStyle levelStyle
+var loggingWriteErrorHandler func(error)
+var loggingMaxFields int
+var loggingCollapseBlankLines bool
+var loggingMultiline multilineMode
+var errorTotalSize *totalSizeCap
+
+// lineCounter is called with the level of every log line written, when set
+var lineCounter func(Level)
 var logger *lumberjack.Logger
 
+// nowFunc returns the time of log lines, tests replace it with SetClock
+var nowFunc = time.Now
+
+// exitFunc is called by Fatalf, tests replace it to keep the process running
+var exitFunc = os.Exit
+
 const defaultTimestampFormat = time.RFC3339
+const defaultPanicStackSize = 16 * 1024
+const defaultMaxFields = 100
 
 // LogOptions specifies the configuration of the log
 type LogOptions struct {
@@ -52,19 +153,89 @@ type LogOptions struct {
 	MaxSize    *int  `json:"maxSize,omitempty"`
 	MaxBackups *int  `json:"maxBackups,omitempty"`
 	Compress   *bool `json:"compress,omitempty"`
+	// MaxTotalSize is the maximum size in megabytes of the log file and its
+	// backups together. After a rotation, the oldest backups are removed
+	// until they fit, in addition to the backups removed by MaxBackups and
+	// MaxAge. Unset or zero does not limit the total size
+	MaxTotalSize *int `json:"maxTotalSize,omitempty"`
+	// FileMode is the permission of the log file in octal, such as "0600".
+	// The rotated log files keep it. Unset keeps the permission of an
+	// existing log file, and creates a new one with 0644
+	FileMode *string `json:"fileMode,omitempty"`
+	// CompressFormat is the format the backups are compressed with when
+	// Compress is set, "gzip" or "zstd". Unset uses gzip. The backups
+	// compressed with zstd get the .zst suffix
+	CompressFormat *string `json:"compressFormat,omitempty"`
+}
+
+// validate checks that the options are in range
+func (o *LogOptions) validate() error {
+	for _, opt := range []struct {
+		name  string
+		value *int
+	}{
+		{"maxAge", o.MaxAge},
+		{"maxSize", o.MaxSize},
+		{"maxBackups", o.MaxBackups},
+		{"maxTotalSize", o.MaxTotalSize},
+	} {
+		if opt.value != nil && *opt.value < 0 {
+			return fmt.Errorf("invalid log option %s: %d, must not be negative", opt.name, *opt.value)
+		}
+	}
+	if o.FileMode != nil {
+		if _, err := parseFileMode(*o.FileMode); err != nil {
+			return err
+		}
+	}
+	if o.CompressFormat != nil && *o.CompressFormat != compressFormatGzip && *o.CompressFormat != compressFormatZstd {
+		return fmt.Errorf("invalid log option compressFormat: %q, must be gzip or zstd", *o.CompressFormat)
+	}
+	return nil
 }
 
-// SetLogOptions set the LoggingOptions of NetConf
-func SetLogOptions(options *LogOptions) {
-	// give some default value
+// parseFileMode parses the octal permission of the fileMode log option
+func parseFileMode(s string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("invalid log option fileMode: %q, must be an octal permission such as 0600", s)
+	}
+	return os.FileMode(mode), nil
+}
+
+// SetLogOptions set the LoggingOptions of NetConf, the unset options get the
+// defaults of SetDefaults. It returns an error, and keeps the current options,
+// when an option is out of range
+func SetLogOptions(options *LogOptions) error {
+	if options != nil {
+		if err := options.validate(); err != nil {
+			return err
+		}
+	}
+	var mode os.FileMode
+	if options != nil && options.FileMode != nil {
+		mode, _ = parseFileMode(*options.FileMode)
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	ensureLogger()
+	if mode != 0 {
+		for _, l := range []*lumberjack.Logger{logger, errorLogger} {
+			if l == nil || l.Filename == "" {
+				continue
+			}
+			if err := os.Chmod(l.Filename, mode); err != nil {
+				return fmt.Errorf("failed to set the mode of log file %s: %w", l.Filename, err)
+			}
+		}
+	}
+	loggingFileMode = mode
+
 	updatedLogger := lumberjack.Logger{
-		Filename:   logger.Filename,
-		MaxAge:     5,
-		MaxBackups: 5,
-		Compress:   true,
-		MaxSize:    100,
-		LocalTime:  logger.LocalTime,
+		Filename:  logger.Filename,
+		LocalTime: logger.LocalTime,
 	}
+	loggingFileDefaults.apply(&updatedLogger)
 	if options != nil {
 		if options.MaxAge != nil {
 			updatedLogger.MaxAge = *options.MaxAge
@@ -79,8 +250,32 @@ func SetLogOptions(options *LogOptions) {
 			updatedLogger.Compress = *options.Compress
 		}
 	}
+	// lumberjack only compresses with gzip, so the backups are compressed by
+	// the total size cap instead
+	zstd := updatedLogger.Compress && options != nil && options.CompressFormat != nil &&
+		*options.CompressFormat == compressFormatZstd
+	if zstd {
+		updatedLogger.Compress = false
+	}
 	logger = &updatedLogger
-	loggingW = logger
+	loggingOptionsSet = true
+	if loggingDaily != nil {
+		loggingDaily.update(mode, logger.MaxAge, loggingUTC)
+	} else if loggingGzip == nil {
+		loggingW = logger
+	}
+	loggingTotalSize = nil
+	var maxTotalSize int
+	if options != nil && options.MaxTotalSize != nil {
+		maxTotalSize = *options.MaxTotalSize
+	}
+	if maxTotalSize > 0 || zstd {
+		loggingTotalSize = newTotalSizeCap(logger, maxTotalSize, zstd)
+	}
+	if errorLogger != nil {
+		setErrorLogger(errorLogger.Filename)
+	}
+	return nil
 }
 
 func (l Level) String() string {
@@ -91,94 +286,1156 @@ func (l Level) String() string {
 		return "verbose"
 	case ErrorLevel:
 		return "error"
+	case WarningLevel:
+		return "warning"
 	case DebugLevel:
 		return "debug"
+	case TraceLevel:
+		return "trace"
+	case FatalLevel:
+		return "fatal"
 	}
 	return "unknown"
 }
 
-func printf(level Level, format string, a ...interface{}) {
-	header := "%s [%s] "
-	t := time.Now()
-	if level > loggingLevel {
-		return
+// callerDepth is the number of stack frames between caller() and the code
+// calling the exported logging functions, which must call printf or printfn
+// directly. Adapters add the frames of the logging library calling them, and
+// the package functions wrapping the LogEntry methods the frame of the
+// wrapper (see LogEntry and packageLogger)
+const callerDepth = 6
+
+// severe reports whether messages of the level report errors
+func (l Level) severe() bool {
+	return l <= ErrorLevel || l == FatalLevel
+}
+
+// levelEnabled reports whether a message of given level passes the threshold
+func levelEnabled(level, threshold Level) bool {
+	return level <= threshold || level == FatalLevel
+}
+
+// logEnabled reports whether a message of given level from e is logged: it
+// passes the threshold, and there is a sink for it. The caller must hold
+// loggingMutex
+func logEnabled(e *LogEntry, level Level) bool {
+	return hasSink() && levelEnabled(level, threshold(e))
+}
+
+// hasSink reports whether log lines are written or observed anywhere, so
+// that logging calls do nothing when all of the outputs are disabled. The
+// caller must hold loggingMutex
+func hasSink() bool {
+	return loggingStderr || loggingStdout || loggingW != nil ||
+		len(loggingOutputs) > 0 || loggingSyslog != nil || loggingSocket != nil ||
+		loggingKmsg != nil || errorLogger != nil || loggingRing != nil || len(loggingHooks) > 0 ||
+		loggingRecordSink != nil || lineCounter != nil || loggingCapture != nil
+}
+
+func printf(e *LogEntry, level Level, format string, a ...interface{}) {
+	logf(e, level, format, a).run()
+}
+
+// logf logs the message, and returns the call of the hooks to make once
+// loggingMutex is released
+func logf(e *LogEntry, level Level, format string, a []interface{}) hookCall {
+	if !mayLog(e, level) {
+		return hookCall{}
+	}
+	loggingMutex.RLock()
+	if !logEnabled(e, level) {
+		loggingMutex.RUnlock()
+		return hookCall{}
+	}
+	t := nowFunc()
+	msg := trimMessage(fmt.Sprintf(format, a...))
+	filters := levelFilters(level)
+	loggingMutex.RUnlock()
+	return logFiltered(e, level, t, msg, filters, logEnabled)
+}
+
+// printfn is printf with a message built by msg, which is only called when
+// the level passes the threshold
+func printfn(e *LogEntry, level Level, msg func() string) {
+	logfn(e, level, msg).run()
+}
+
+// logfn is logf with a message built by msg
+func logfn(e *LogEntry, level Level, msg func() string) hookCall {
+	if !mayLog(e, level) {
+		return hookCall{}
+	}
+	loggingMutex.RLock()
+	if !logEnabled(e, level) {
+		loggingMutex.RUnlock()
+		return hookCall{}
+	}
+	t := nowFunc()
+	m := trimMessage(msg())
+	filters := levelFilters(level)
+	loggingMutex.RUnlock()
+	return logFiltered(e, level, t, m, filters, logEnabled)
+}
+
+// logFiltered logs msg created at t, unless one of filters drops it. The
+// filters run without loggingMutex, so that they can log and configure
+// logging, and enabled is checked again under it, as the configuration may
+// have changed meanwhile
+func logFiltered(e *LogEntry, level Level, t time.Time, msg string, filters []Filter, enabled func(*LogEntry, Level) bool) hookCall {
+	for _, fn := range filters {
+		if !fn(level, msg) {
+			return hookCall{}
+		}
+	}
+	loggingMutex.RLock()
+	defer loggingMutex.RUnlock()
+	if !enabled(e, level) || !logRecord(e, level, t, msg) {
+		return hookCall{}
+	}
+	return newHookCall(e, level, t, msg)
+}
+
+// logRecord logs msg created at t, and reports whether it was written rather
+// than suppressed. The caller must hold loggingMutex
+func logRecord(e *LogEntry, level Level, t time.Time, msg string) bool {
+	if loggingUTC {
+		t = t.UTC()
+	}
+	r := record{
+		time:  t,
+		level: level,
+		msg:   msg,
+	}
+	if e != nil {
+		r.component = e.component
+		r.fields = e.fields
+	}
+
+	if loggingDedup != nil {
+		repeated, duplicate := loggingDedup.check(&r)
+		if repeated != nil {
+			output(repeated)
+		}
+		if duplicate {
+			return false
+		}
+	}
+
+	if s, ok := loggingSamplers[level]; ok && !s.keep() {
+		return false
 	}
 
-	if loggingStderr {
-		fmt.Fprintf(os.Stderr, header, t.Format(defaultTimestampFormat), level)
-		fmt.Fprintf(os.Stderr, format, a...)
-		fmt.Fprintf(os.Stderr, "\n")
+	if loggingRateLimiter != nil && !(loggingRateLimitBypass && level.severe()) && level != FatalLevel {
+		allowed, suppressed := loggingRateLimiter.allow(t)
+		if !allowed {
+			return false
+		}
+		if suppressed > 0 {
+			reportSuppressed(t, suppressed)
+		}
+	}
+
+	if loggingCaller {
+		if e != nil && e.pc != 0 {
+			r.caller = callerAt(e.pc)
+		} else {
+			depth := callerDepth
+			if e != nil {
+				depth += e.callDepth
+			}
+			r.caller = caller(depth)
+		}
 	}
+	output(&r)
+	return true
+}
 
-	if loggingW != nil {
-		fmt.Fprintf(loggingW, header, t.Format(defaultTimestampFormat), level)
-		fmt.Fprintf(loggingW, format, a...)
-		fmt.Fprintf(loggingW, "\n")
+// output formats the record and writes it to the log destinations
+func output(r *record) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	switch loggingFormat {
+	case jsonFormat:
+		formatJSON(buf, r)
+	case syslogFormat:
+		formatSyslog(buf, r)
+	default:
+		formatText(buf, r)
+	}
+	line := buf.Bytes()
+	if len(loggingRedactors) > 0 {
+		line = redact(line)
+	}
+	writeLine(r.level, line)
+	if loggingRing != nil {
+		loggingRing.add(line)
+	}
+	if loggingCapture != nil {
+		loggingCapture.add(r.level, line)
+	}
+	if lineCounter != nil {
+		lineCounter(r.level)
 	}
 }
 
+// caller returns the file:line of the code calling the logging functions,
+// depth frames up the stack
+func caller(depth int) string {
+	_, file, line, ok := runtime.Caller(depth)
+	if !ok {
+		return "???:0"
+	}
+	return fmt.Sprintf("%s:%d", filepath.Base(file), line)
+}
+
+// callerAt returns the file:line of the code at program counter pc
+func callerAt(pc uintptr) string {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.File == "" {
+		return "???:0"
+	}
+	return fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+}
+
+// destinations are the writers a log line is written to
+type destinations struct {
+	stderr  bool
+	stdout  bool
+	w       io.Writer
+	outputs []io.Writer
+	syslog  *syslog.Writer
+	socket  *socketWriter
+	// kmsg is the kernel message buffer, which gets the lines of severe levels
+	kmsg io.Writer
+	// color is set when stderr output is colorized
+	color bool
+	// stderrLevel is the threshold of the lines written to stderr
+	stderrLevel Level
+	// totalSize is set when w is the log file with a total size cap
+	totalSize *totalSizeCap
+	// logDir and file are set when w is the log file, to reopen it when gone
+	logDir *logDirRecovery
+	file   *lumberjack.Logger
+	// errorW is the error log file, which gets the lines of severe levels
+	errorW         io.Writer
+	errorTotalSize *totalSizeCap
+	// writeError is called with the errors writing to w or errorW
+	writeError func(error)
+}
+
+// currentDestinations returns the configured destinations. The caller must
+// hold loggingMutex
+func currentDestinations() destinations {
+	d := destinations{
+		stderr:  loggingStderr,
+		stdout:  loggingStdout,
+		color:   colorStderr(),
+		w:       loggingW,
+		outputs: loggingOutputs,
+		syslog:  loggingSyslog,
+		socket:  loggingSocket,
+
+		stderrLevel: loggingStderrLevel,
+		writeError:  loggingWriteErrorHandler,
+	}
+	if loggingTotalSize != nil && loggingW == io.Writer(logger) {
+		d.totalSize = loggingTotalSize
+	}
+	if loggingLogDir != nil && loggingW == io.Writer(logger) {
+		d.logDir = loggingLogDir
+		d.file = logger
+	}
+	if errorLogger != nil {
+		d.errorW = errorLogger
+		d.errorTotalSize = errorTotalSize
+	}
+	if loggingKmsg != nil {
+		d.kmsg = loggingKmsg
+	}
+	return d
+}
+
+// writeLine writes the line to the destinations, through the async writer
+// when enabled. Lines of severe levels are always written synchronously,
+// after the queued lines
+func writeLine(level Level, line []byte) {
+	d := currentDestinations()
+	if loggingAsync != nil {
+		if !level.severe() {
+			loggingAsync.enqueue(level, line, d)
+			return
+		}
+		loggingAsync.flush()
+	}
+	d.write(level, line)
+}
+
+// write issues a single Write of the line per destination, so that
+// concurrent log lines do not interleave
+func (d destinations) write(level Level, line []byte) {
+	if d.stderr && levelEnabled(level, d.stderrLevel) {
+		if d.color {
+			os.Stderr.Write(colorize(level, line))
+		} else {
+			os.Stderr.Write(line)
+		}
+	}
+
+	if d.stdout {
+		os.Stdout.Write(line)
+	}
+
+	if d.w != nil {
+		err := writeFile(d.w, d.totalSize, line)
+		if d.logDir != nil {
+			reopened, rerr := d.logDir.recover(d.file, err)
+			if rerr != nil {
+				reportWriteError(d.writeError, rerr)
+			}
+			if reopened {
+				err = writeFile(d.w, d.totalSize, line)
+			}
+		}
+		if err != nil {
+			reportWriteError(d.writeError, fmt.Errorf("failed to write the log file: %w", err))
+		}
+	}
+
+	if d.errorW != nil && level.severe() {
+		if err := writeFile(d.errorW, d.errorTotalSize, line); err != nil {
+			reportWriteError(d.writeError, fmt.Errorf("failed to write the error log file: %w", err))
+		}
+	}
+
+	// a failing output must not keep the line from the others
+	for _, w := range d.outputs {
+		w.Write(line)
+	}
+
+	if d.syslog != nil {
+		writeSyslog(d.syslog, level, line)
+	}
+
+	if d.socket != nil {
+		d.socket.Write(line)
+	}
+
+	if d.kmsg != nil && level.severe() {
+		if err := writeKmsg(d.kmsg, level, line); err != nil {
+			reportWriteError(d.writeError, fmt.Errorf("failed to write the kernel message buffer: %w", err))
+		}
+	}
+}
+
+// writeFile writes the line to log file w, accounting it in the total size
+// cap c when set
+func writeFile(w io.Writer, c *totalSizeCap, line []byte) error {
+	if _, err := w.Write(line); err != nil {
+		return err
+	}
+	if c != nil {
+		c.wrote(len(line))
+	}
+	return nil
+}
+
+// valid reports whether the level is a logging level or FatalLevel
+func (l Level) valid() bool {
+	return l < MaxLevel || l == FatalLevel
+}
+
+// Logf prints logging if logging level >= level, for a level chosen at run
+// time. Unlike Errorf, Panicf and Fatalf it only prints the message: no error
+// is returned, no stack trace printed and the process does not exit. Invalid
+// levels, such as UnknownLevel, print nothing
+func Logf(level Level, format string, a ...interface{}) {
+	if !level.valid() {
+		return
+	}
+	printf(nil, level, format, a...)
+}
+
+// Raw prints msg as is if logging level >= level, with no format expansion,
+// so that text such as captured command output or untrusted input is logged
+// literally. Invalid levels print nothing, as for Logf
+func Raw(level Level, msg string) {
+	if !level.valid() {
+		return
+	}
+	printf(nil, level, "%s", msg)
+}
+
+// Trace prints msg as is if logging level >= trace. Unlike Tracef, msg is
+// not a format, so that untrusted input cannot inject format verbs
+func Trace(msg string) {
+	printf(nil, TraceLevel, "%s", msg)
+}
+
+// Debug prints msg as is if logging level >= debug
+func Debug(msg string) {
+	printf(nil, DebugLevel, "%s", msg)
+}
+
+// Verbose prints msg as is if logging level >= verbose
+func Verbose(msg string) {
+	printf(nil, VerboseLevel, "%s", msg)
+}
+
+// Info prints msg as is if logging level >= info, which is verbose
+func Info(msg string) {
+	printf(nil, InfoLevel, "%s", msg)
+}
+
+// Warning prints msg as is if logging level >= warning
+func Warning(msg string) {
+	printf(nil, WarningLevel, "%s", msg)
+}
+
+// Error prints msg as is if logging level >= error, and returns an error
+// with msg as its message. Unlike Errorf, msg is not a format, so that an
+// error message derived from untrusted input is neither garbled by nor
+// misread as format verbs
+func Error(msg string) error {
+	printf(nil, ErrorLevel, "%s", msg)
+	return errors.New(msg)
+}
+
+// Tracef prints logging if logging level >= trace
+func Tracef(format string, a ...interface{}) {
+	packageLogger.Tracef(format, a...)
+}
+
 // Debugf prints logging if logging level >= debug
 func Debugf(format string, a ...interface{}) {
-	printf(DebugLevel, format, a...)
+	packageLogger.Debugf(format, a...)
+}
+
+// Debugfn prints the message returned by msg if logging level >= debug. msg
+// is not called otherwise
+func Debugfn(msg func() string) {
+	printfn(nil, DebugLevel, msg)
 }
 
 // Verbosef prints logging if logging level >= verbose
 func Verbosef(format string, a ...interface{}) {
-	printf(VerboseLevel, format, a...)
+	packageLogger.Verbosef(format, a...)
+}
+
+// Verbosefn prints the message returned by msg if logging level >= verbose.
+// msg is not called otherwise
+func Verbosefn(msg func() string) {
+	printfn(nil, VerboseLevel, msg)
 }
 
-// Errorf prints logging if logging level >= error
+// Infof prints logging if logging level >= info, which is verbose
+func Infof(format string, a ...interface{}) {
+	packageLogger.Infof(format, a...)
+}
+
+// Warningf prints logging if logging level >= warning
+func Warningf(format string, a ...interface{}) {
+	packageLogger.Warningf(format, a...)
+}
+
+// Errorf prints logging if logging level >= error. The returned error wraps
+// the argument of a %w verb, like fmt.Errorf
 func Errorf(format string, a ...interface{}) error {
-	printf(ErrorLevel, format, a...)
-	return fmt.Errorf(format, a...)
+	return packageLogger.Errorf(format, a...)
+}
+
+// Errorfn prints the message returned by msg if logging level >= error. msg
+// is not called otherwise, so unlike Errorf no error is returned
+func Errorfn(msg func() string) {
+	printfn(nil, ErrorLevel, msg)
+}
+
+// WrapError prints logging if logging level >= error, and returns an error
+// wrapping err, annotated with the message
+func WrapError(err error, format string, a ...interface{}) error {
+	wrapped := fmt.Errorf("%s: %w", fmt.Sprintf(format, a...), err)
+	printf(nil, ErrorLevel, "%s", wrapped)
+	return wrapped
+}
+
+// ErrorErr prints logging if logging level >= error, annotated with err, and
+// returns err, so that call sites can return logging.ErrorErr(err, "...").
+// The log line gets an error field with the message of err, and in json
+// format an errorChain field with the messages of err and of the errors it
+// wraps, as unwrapped by errors.Unwrap
+func ErrorErr(err error, format string, a ...interface{}) error {
+	printf((&LogEntry{}).withError(err), ErrorLevel, format, a...)
+	return err
 }
 
 // Panicf prints logging plus stack trace. This should be used only for unrecoverable error
 func Panicf(format string, a ...interface{}) {
-	printf(PanicLevel, format, a...)
-	printf(PanicLevel, "========= Stack trace output ========")
-	printf(PanicLevel, "%+v", errors.New("Multus Panic"))
-	printf(PanicLevel, "========= Stack trace output end ========")
+	packageLogger.Panicf(format, a...)
+}
+
+// PanicFormat specifies how Panicf prints the stack trace. The zero value is
+// the default: banners around the whole stack trace
+type PanicFormat struct {
+	// NoBanners omits the lines around the stack trace
+	NoBanners bool
+	// MaxFrames limits the stack trace to the frames of the caller of
+	// Panicf and its first MaxFrames-1 callers. Zero does not limit it
+	MaxFrames int
+}
+
+// SetPanicFormat sets how Panicf prints the stack trace
+func SetPanicFormat(format PanicFormat) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	panicFormat = format
+}
+
+// stackSkipFrames is the number of frames of the logging package at the top
+// of the stack trace: stack() and Panicf
+const stackSkipFrames = 2
+
+// stack returns whether the stack trace is printed with banners, and the
+// stack trace of the current goroutine, truncated to the panic stack size
+// and limited to the frames of the panic format. callDepth is the number of
+// frames between Panicf and the code logging, as in LogEntry
+func stack(callDepth int) (bool, []byte) {
+	loggingMutex.RLock()
+	format := panicFormat
+	buf := make([]byte, panicStackSize)
+	loggingMutex.RUnlock()
+	n := runtime.Stack(buf, false)
+	trace := bytes.TrimRight(buf[:n], "\n")
+	if format.MaxFrames > 0 {
+		trace = limitFrames(trace, stackSkipFrames+callDepth, format.MaxFrames)
+	}
+	return !format.NoBanners, trace
+}
+
+// limitFrames returns the goroutine header line of the stack trace, followed
+// by n frames after the first skip ones. Each frame takes two lines: the
+// function, and its file:line
+func limitFrames(trace []byte, skip, n int) []byte {
+	lines := bytes.Split(trace, []byte("\n"))
+	header, frames := lines[:1], lines[1:]
+	start := 2 * skip
+	if start > len(frames) {
+		start = len(frames)
+	}
+	end := start + 2*n
+	if end > len(frames) {
+		end = len(frames)
+	}
+	return bytes.Join(append(header, frames[start:end]...), []byte("\n"))
+}
+
+// Fatalf prints logging and exits the process with status 1. This should be used
+// only when Multus cannot continue
+func Fatalf(format string, a ...interface{}) {
+	packageLogger.Fatalf(format, a...)
 }
 
 // GetLoggingLevel gets current logging level
 func GetLoggingLevel() Level {
-	return loggingLevel
+	return loggingLevel.Load()
 }
 
-func getLoggingLevel(levelStr string) Level {
+// GetLogFile gets current logging file, empty when logging to a file is not set
+func GetLogFile() string {
+	loggingMutex.RLock()
+	defer loggingMutex.RUnlock()
+	if logger == nil {
+		return ""
+	}
+	return logger.Filename
+}
+
+// GetLogOptions gets current LogOptions of the logging file. The returned
+// options do not share memory with the logging configuration
+func GetLogOptions() LogOptions {
+	loggingMutex.RLock()
+	defer loggingMutex.RUnlock()
+	l := logger
+	if l == nil {
+		l = &lumberjack.Logger{}
+	}
+	maxAge, maxSize, maxBackups, compress := l.MaxAge, l.MaxSize, l.MaxBackups, l.Compress
+	var maxTotalSize int
+	zstd := loggingTotalSize != nil && loggingTotalSize.zstd
+	if loggingTotalSize != nil {
+		maxTotalSize = loggingTotalSize.maxTotalSize
+	}
+	if zstd {
+		compress = true
+	}
+	options := LogOptions{
+		MaxAge:       &maxAge,
+		MaxSize:      &maxSize,
+		MaxBackups:   &maxBackups,
+		Compress:     &compress,
+		MaxTotalSize: &maxTotalSize,
+	}
+	if loggingFileMode != 0 {
+		mode := fmt.Sprintf("%#o", loggingFileMode)
+		options.FileMode = &mode
+	}
+	if zstd {
+		format := compressFormatZstd
+		options.CompressFormat = &format
+	}
+	return options
+}
+
+// GetLogStderr gets current flag for logging stderr output
+func GetLogStderr() bool {
+	loggingMutex.RLock()
+	defer loggingMutex.RUnlock()
+	return loggingStderr
+}
+
+// GetLogStdout gets current flag for logging stdout output
+func GetLogStdout() bool {
+	loggingMutex.RLock()
+	defer loggingMutex.RUnlock()
+	return loggingStdout
+}
+
+// Enabled reports whether messages of given level are logged, which is never
+// the case when all of the outputs are disabled. It lets callers skip
+// building expensive arguments when they would be dropped:
+//
+//	if logging.DebugEnabled() {
+//		data, _ := json.Marshal(netconf)
+//		logging.Debugf("netconf: %s", data)
+//	}
+func Enabled(level Level) bool {
+	if !mayLog(nil, level) {
+		return false
+	}
+	loggingMutex.RLock()
+	defer loggingMutex.RUnlock()
+	return logEnabled(nil, level)
+}
+
+// DebugEnabled reports whether debug messages are logged
+func DebugEnabled() bool {
+	return Enabled(DebugLevel)
+}
+
+// VerboseEnabled reports whether verbose messages are logged
+func VerboseEnabled() bool {
+	return Enabled(VerboseLevel)
+}
+
+// UnknownLevelError is returned by ParseLevel for an unrecognized level name
+type UnknownLevelError struct {
+	Level string
+}
+
+func (e *UnknownLevelError) Error() string {
+	names := []string{}
+	for _, level := range []Level{TraceLevel, DebugLevel, VerboseLevel, WarningLevel, ErrorLevel, PanicLevel} {
+		names = append(names, level.String())
+	}
+	return fmt.Sprintf("unknown logging level %q, accepted levels are: %s", e.Level, strings.Join(names, ", "))
+}
+
+// ParseLevel converts a level name into a Level. It takes the number of a
+// level as well, as SetLogLevelNum, e.g. "3" is VerboseLevel
+func ParseLevel(levelStr string) (Level, error) {
 	switch strings.ToLower(levelStr) {
+	case "trace":
+		return TraceLevel, nil
 	case "debug":
-		return DebugLevel
+		return DebugLevel, nil
 	case "verbose":
-		return VerboseLevel
+		return VerboseLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warning", "warn":
+		return WarningLevel, nil
 	case "error":
-		return ErrorLevel
+		return ErrorLevel, nil
 	case "panic":
-		return PanicLevel
+		return PanicLevel, nil
+	}
+	if level, ok := numberedLevel(levelStr); ok {
+		return level, nil
+	}
+	return UnknownLevel, &UnknownLevelError{Level: levelStr}
+}
+
+// MarshalText encodes the level as its name, e.g. "debug". Levels without a
+// name, such as UnknownLevel, are an error
+func (l Level) MarshalText() ([]byte, error) {
+	name := l.String()
+	if name == "unknown" {
+		return nil, fmt.Errorf("cannot marshal unknown logging level %d", uint32(l))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText decodes a level name, as accepted by ParseLevel, or "fatal"
+func (l *Level) UnmarshalText(text []byte) error {
+	name := string(text)
+	if strings.ToLower(name) == "fatal" {
+		*l = FatalLevel
+		return nil
+	}
+	level, err := ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// MarshalJSON encodes the level as its name, as MarshalText
+func (l Level) MarshalJSON() ([]byte, error) {
+	text, err := l.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON decodes a level name, as UnmarshalText, or the number of a
+// level, as SetLogLevelNum
+func (l *Level) UnmarshalJSON(data []byte) error {
+	if level, ok := numberedLevel(string(data)); ok {
+		*l = level
+		return nil
+	}
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("logging level must be a name or a number from 0 to %d: %w", len(numericLevels)-1, err)
+	}
+	return l.UnmarshalText([]byte(name))
+}
+
+// internalWarningf logs a problem of the logging configuration itself at
+// warning level, whatever the logging level, so that it reaches the log
+// destinations rather than getting filtered out. It must be called by the
+// setters themselves
+func internalWarningf(format string, a ...interface{}) {
+	internalWarningCall(format, a).run()
+}
+
+func internalWarningCall(format string, a []interface{}) hookCall {
+	loggingMutex.RLock()
+	if !hasSink() {
+		loggingMutex.RUnlock()
+		return hookCall{}
+	}
+	t := nowFunc()
+	msg := fmt.Sprintf(format, a...)
+	filters := levelFilters(WarningLevel)
+	loggingMutex.RUnlock()
+	return logFiltered(nil, WarningLevel, t, msg, filters, func(*LogEntry, Level) bool {
+		return hasSink()
+	})
+}
+
+// SetLogLevel sets logging level. It returns an error, logged as well, and
+// keeps the current level when levelStr is not a logging level
+func SetLogLevel(levelStr string) error {
+	level, err := ParseLevel(levelStr)
+	if err != nil || level >= MaxLevel {
+		internalWarningf("multus logging: cannot set logging level to %s", levelStr)
+		if err == nil {
+			err = &UnknownLevelError{Level: levelStr}
+		}
+		return err
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	setLevel(level)
+	return nil
+}
+
+// numericLevels maps the numeric verbosity of SetLogLevelNum and V, in the
+// style of klog, to the logging levels
+var numericLevels = []Level{
+	0: PanicLevel,
+	1: ErrorLevel,
+	2: WarningLevel,
+	3: VerboseLevel,
+	4: DebugLevel,
+	5: TraceLevel,
+}
+
+// numericLevel returns the logging level of verbosity n, clamped to the
+// verbosities of numericLevels
+func numericLevel(n int) Level {
+	if n < 0 {
+		n = 0
+	}
+	if n >= len(numericLevels) {
+		n = len(numericLevels) - 1
+	}
+	return numericLevels[n]
+}
+
+// numberedLevel returns the logging level of the numeric verbosity s, and
+// false when s is not one of numericLevels
+func numberedLevel(s string) (Level, bool) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n >= len(numericLevels) {
+		return UnknownLevel, false
+	}
+	return numericLevels[n], true
+}
+
+// SetLogLevelNum sets logging level from a numeric verbosity: 0 is panic,
+// 1 error, 2 warning, 3 verbose, 4 debug and 5 trace. Verbosities below 0 are
+// taken as 0, and above 5 as 5
+func SetLogLevelNum(n int) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	setLevel(numericLevel(n))
+}
+
+// WithLevel sets the logging level and returns a function restoring the
+// previous one, e.g. to debug a block of code with
+//
+//	defer logging.WithLevel(logging.DebugLevel)()
+//
+// The logging level is global: it applies to all goroutines until restored.
+// Calling the function again does nothing. Levels other than PanicLevel to
+// TraceLevel leave the logging level unchanged
+func WithLevel(level Level) (restore func()) {
+	if level >= MaxLevel {
+		return func() {}
+	}
+
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	previous := loggingLevel.Load()
+	setLevel(level)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			loggingMutex.Lock()
+			defer loggingMutex.Unlock()
+			setLevel(previous)
+		})
+	}
+}
+
+// V reports whether messages of numeric verbosity n, as in SetLogLevelNum,
+// are logged at the current logging level
+func V(n int) bool {
+	return Enabled(numericLevel(n))
+}
+
+// Writer returns the current log file destination, or a writer discarding
+// everything when logging to file is disabled. Writes through it bypass the
+// logging level filter and the log line formatting
+func Writer() io.Writer {
+	loggingMutex.RLock()
+	defer loggingMutex.RUnlock()
+	if loggingW == nil {
+		return io.Discard
+	}
+	return loggingW
+}
+
+// SetOutput sets the writer log lines are written to in place of the log
+// file, nil disables it
+func SetOutput(w io.Writer) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingW = w
+}
+
+// ResetOutput restores the log file as the writer log lines are written to
+func ResetOutput() {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	ensureLogger()
+	if logger.Filename == "" {
+		loggingW = nil
+		return
+	}
+	loggingW = logger
+}
+
+// AddOutput adds a writer log lines are written to, in addition to the log
+// file and stderr
+func AddOutput(w io.Writer) {
+	if w == nil {
+		return
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingOutputs = append(loggingOutputs, w)
+}
+
+// ClearOutputs removes the writers added by AddOutput
+func ClearOutputs() {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingOutputs = nil
+}
+
+// SetLineCounter sets a function called with the level of every log line
+// written, after level filtering, e.g. to count them. nil removes it
+func SetLineCounter(fn func(Level)) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	lineCounter = fn
+}
+
+// SetLogFormat sets the format of log lines, "text", "json" or "syslog". The
+// syslog format is the RFC 5424 one, see SetSyslogLineOptions
+func SetLogFormat(format string) error {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	switch strings.ToLower(format) {
+	case "text":
+		loggingFormat = textFormat
+	case "json":
+		loggingFormat = jsonFormat
+	case "syslog":
+		loggingFormat = syslogFormat
+	default:
+		return fmt.Errorf("multus logging: unknown log format %q", format)
+	}
+	return nil
+}
+
+// SchemaVersion is the version of the json log lines, in their schemaVersion
+// field. It changes when their fields change incompatibly
+const SchemaVersion = "1"
+
+// schemaField is the field of json log lines with the schema version
+const schemaField = "schemaVersion"
+
+// SetSchemaVersion sets the schemaVersion field of json log lines, e.g. by
+// programs wrapping the package with their own fields. An empty version
+// restores SchemaVersion
+func SetSchemaVersion(v string) {
+	if v == "" {
+		v = SchemaVersion
+	}
+
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingSchemaVersion = v
+}
+
+// SetTimestamp sets flag for the timestamp at the beginning of log lines
+func SetTimestamp(enable bool) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingTimestamp = enable
+}
+
+// SetTimestampFormat sets the layout of the timestamp, as for time.Format. An
+// empty layout restores the default, RFC3339
+func SetTimestampFormat(layout string) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	if layout == "" {
+		layout = defaultTimestampFormat
+	}
+	loggingTimestampFormat = layout
+}
+
+// Precision is the sub-second precision of timestamps
+type Precision int
+
+// Timestamp precisions
+const (
+	SecondPrecision Precision = iota
+	MillisecondPrecision
+	MicrosecondPrecision
+	NanosecondPrecision
+)
+
+// precisionFormats are the RFC3339 timestamp formats of the precisions
+var precisionFormats = map[Precision]string{
+	SecondPrecision:      time.RFC3339,
+	MillisecondPrecision: "2006-01-02T15:04:05.000Z07:00",
+	MicrosecondPrecision: "2006-01-02T15:04:05.000000Z07:00",
+	NanosecondPrecision:  "2006-01-02T15:04:05.000000000Z07:00",
+}
+
+// SetTimestampPrecision sets the timestamp format to RFC3339 with the given
+// sub-second precision, e.g. 2023-01-02T03:04:05.678Z for
+// MillisecondPrecision. It is a shorthand for SetTimestampFormat, the last
+// call of either wins. An unknown precision is ignored
+func SetTimestampPrecision(p Precision) {
+	layout, ok := precisionFormats[p]
+	if !ok {
+		return
+	}
+	SetTimestampFormat(layout)
+}
+
+// SetClock sets the function returning the time of log lines, nil restores
+// time.Now. It is meant for tests only, to freeze the timestamps of log
+// lines, and must not be called while other goroutines log. Reset restores
+// time.Now
+func SetClock(fn func() time.Time) {
+	if fn == nil {
+		fn = time.Now
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	nowFunc = fn
+}
+
+// SetLocalTime sets flag for the log file backup names in local time rather
+// than in UTC, which lumberjack uses until set. Timestamps follow the same
+// time zone. It is the inverse of SetUTC, and the last call of either wins
+func SetLocalTime(enable bool) {
+	SetUTC(!enable)
+}
+
+// SetUTC sets flag for timestamps in UTC rather than in local time. The log
+// file backup names follow the same time zone, see SetLocalTime
+func SetUTC(enable bool) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingUTC = enable
+	ensureLogger()
+	updatedLogger := copyLogger(logger)
+	updatedLogger.LocalTime = !enable
+	if loggingW == io.Writer(logger) {
+		loggingW = updatedLogger
+	}
+	logger = updatedLogger
+	if loggingTotalSize != nil {
+		loggingTotalSize = newTotalSizeCap(logger, loggingTotalSize.maxTotalSize, loggingTotalSize.zstd)
+	}
+	if loggingDaily != nil {
+		loggingDaily.update(loggingFileMode, logger.MaxAge, enable)
+	}
+	if errorLogger != nil {
+		setErrorLogger(errorLogger.Filename)
+	}
+}
+
+// ensureLogger sets an empty lumberjack logger when there is none, so that
+// the setters reading its configuration do not depend on initialization
+// order. The caller must hold loggingMutex
+func ensureLogger() {
+	if logger == nil {
+		logger = &lumberjack.Logger{}
 	}
-	fmt.Fprintf(os.Stderr, "multus logging: cannot set logging level to %s\n", levelStr)
-	return UnknownLevel
 }
 
-// SetLogLevel sets logging level
-func SetLogLevel(levelStr string) {
-	level := getLoggingLevel(levelStr)
-	if level < MaxLevel {
-		loggingLevel = level
+// copyLogger returns a new lumberjack logger with the configuration of l
+func copyLogger(l *lumberjack.Logger) *lumberjack.Logger {
+	return &lumberjack.Logger{
+		Filename:   l.Filename,
+		MaxAge:     l.MaxAge,
+		MaxBackups: l.MaxBackups,
+		Compress:   l.Compress,
+		MaxSize:    l.MaxSize,
+		LocalTime:  l.LocalTime,
 	}
 }
 
+// SetLogCaller sets flag for prepending the caller's file:line to log messages
+func SetLogCaller(enable bool) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingCaller = enable
+}
+
+// The fields with the host name and the process id of SetIncludeHostname and
+// SetIncludePID
+const (
+	hostField = "host"
+	pidField  = "pid"
+)
+
+// SetIncludeHostname sets flag for adding the host=<hostname> field to log
+// lines, to tell the nodes apart when their logs are shipped centrally. The
+// host name is read when enabling it
+func SetIncludeHostname(enable bool) {
+	var name string
+	if enable {
+		name = hostname()
+	}
+
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingHostname = name
+}
+
+// SetIncludePID sets flag for adding the pid=<pid> field to log lines
+func SetIncludePID(enable bool) {
+	var pid int
+	if enable {
+		pid = os.Getpid()
+	}
+
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingPID = pid
+}
+
+// SetPanicStackSize sets the maximum size in bytes of the stack trace printed
+// by Panicf, zero or less restores the default
+func SetPanicStackSize(size int) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	if size <= 0 {
+		size = defaultPanicStackSize
+	}
+	panicStackSize = size
+}
+
 // SetLogStderr sets flag for logging stderr output
 func SetLogStderr(enable bool) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
 	loggingStderr = enable
+	detectStderrTerminal()
+}
+
+// SetStderrLevel sets the threshold of the log lines written to stderr, e.g.
+// to keep the console terse while the log file gets debug lines. It only
+// drops lines: those above the logging level are not logged anywhere. Levels
+// other than PanicLevel to TraceLevel, such as MaxLevel, make stderr get all
+// of the log lines again
+func SetStderrLevel(level Level) {
+	if level > MaxLevel {
+		level = MaxLevel
+	}
+
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingStderrLevel = level
 }
 
-// SetLogFile sets logging file
-func SetLogFile(filename string) {
+// SetLogStdout sets flag for logging stdout output. It is independent of the
+// stderr flag: when both are set, log lines are written to stderr, then to
+// stdout. The CNI plugin must not log to stdout, which carries its result
+func SetLogStdout(enable bool) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingStdout = enable
+}
+
+// SetLogFile sets logging file. It keeps the log options set by SetLogOptions,
+// or uses the defaults of SetDefaults when it was not called yet. It returns
+// an error, and keeps the current logging file, when filename cannot be
+// opened for writing
+func SetLogFile(filename string) error {
 	if filename == "" {
-		return
+		return nil
 	}
+	loggingMutex.RLock()
+	mode := loggingFileMode
+	loggingMutex.RUnlock()
+	if err := checkWritable(filename, mode); err != nil {
+		return err
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	ensureLogger()
 	updatedLogger := lumberjack.Logger{
 		Filename:   filename,
 		MaxAge:     logger.MaxAge,
@@ -187,13 +1444,197 @@ func SetLogFile(filename string) {
 		MaxSize:    logger.MaxSize,
 		LocalTime:  logger.LocalTime,
 	}
+	if !loggingOptionsSet && loggingFileUsesDefaults {
+		loggingFileDefaults.apply(&updatedLogger)
+	}
+	closeDailyFile()
+	closeGzipFile()
 	logger = &updatedLogger
 	loggingW = logger
+	if loggingTotalSize != nil {
+		loggingTotalSize = newTotalSizeCap(logger, loggingTotalSize.maxTotalSize, loggingTotalSize.zstd)
+	}
+	return nil
 }
 
-func init() {
+// Rotate closes the log file, renames it with a timestamp as a backup, and
+// opens a new log file, regardless of the size and age of the log file. It
+// returns an error when the log file is not set
+func Rotate() error {
+	loggingMutex.RLock()
+	defer loggingMutex.RUnlock()
+	if logger == nil || logger.Filename == "" {
+		return errors.New("cannot rotate the log file: log file is not set")
+	}
+	if err := logger.Rotate(); err != nil {
+		return err
+	}
+	if loggingTotalSize != nil {
+		loggingTotalSize.rotated()
+	}
+	return nil
+}
+
+// checkWritable verifies that the log file can be opened for writing, which
+// lumberjack only does on the first write. The log file is given mode, when
+// set, which lumberjack keeps on rotation
+func checkWritable(filename string, mode os.FileMode) error {
+	perm := mode
+	if perm == 0 {
+		perm = 0644
+	}
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, perm)
+	if err != nil {
+		return fmt.Errorf("failed to open log file %s: %w", filename, err)
+	}
+	if mode != 0 {
+		if err := f.Chmod(mode); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to set the mode of log file %s: %w", filename, err)
+		}
+	}
+	return f.Close()
+}
+
+// Flush logs the number of lines suppressed by the rate limit, waits until
+// the log lines queued by the async writer are written, and makes the inline
+// compressed log file a valid gzip file
+func Flush() error {
+	loggingMutex.RLock()
+	defer loggingMutex.RUnlock()
+	flushRateLimit()
+	if loggingAsync != nil {
+		loggingAsync.flush()
+	}
+	if loggingGzip != nil {
+		return loggingGzip.finish()
+	}
+	return nil
+}
+
+// Close logs the number of lines suppressed by the rate limit, writes the log
+// lines queued by the async writer, closes the log file, syslog, the log
+// socket and the kernel message buffer, stops the time based rotation, and
+// restores the default logging configuration, as Reset. It is meant to be
+// deferred on shutdown, and can be called more than once
+func Close() error {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	flushRateLimit()
+	if loggingAsync != nil {
+		loggingAsync.close()
+		loggingAsync = nil
+	}
+	var errs []error
+	if err := closeGzipFile(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close the inline compressed log file: %w", err))
+	}
+	if logger != nil {
+		if err := logger.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close log file %s: %w", logger.Filename, err))
+		}
+	}
+	if errorLogger != nil {
+		if err := errorLogger.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close error log file %s: %w", errorLogger.Filename, err))
+		}
+	}
+	if err := closeDailyFile(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close daily log file: %w", err))
+	}
+	if loggingSyslog != nil {
+		if err := loggingSyslog.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close syslog: %w", err))
+		}
+		loggingSyslog = nil
+	}
+	if err := closeSocket(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close the log socket: %w", err))
+	}
+	if err := closeKmsg(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close the kernel message buffer: %w", err))
+	}
+	reset()
+	return errors.Join(errs...)
+}
+
+// Reset restores the logging configuration to its initial state
+func Reset() {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	reset()
+}
+
+func reset() {
 	loggingStderr = true
+	loggingStderrLevel = MaxLevel
+	loggingStdout = false
+	closeDailyFile()
+	closeGzipFile()
 	loggingW = nil
-	loggingLevel = PanicLevel
+	loggingOutputs = nil
+	loggingLevel.Store(PanicLevel)
+	loggingComponentMaxLevel.Store(PanicLevel)
+	loggingFormat = textFormat
+	loggingSchemaVersion = SchemaVersion
+	loggingCaller = false
+	loggingHostname = ""
+	loggingPID = 0
+	loggingTimestamp = true
+	loggingTimestampFormat = defaultTimestampFormat
+	loggingUTC = false
+	loggingRateLimiter = nil
+	loggingRateLimitBypass = true
+	loggingDedup = nil
+	loggingRedactors = nil
+	if loggingSyslog != nil {
+		loggingSyslog.Close()
+		loggingSyslog = nil
+	}
+	closeSocket()
+	loggingSocketBufferSize = defaultSocketBufferSize
+	closeKmsg()
+	loggingSyslogApp = defaultSyslogApp
+	loggingSyslogFacility = syslog.LOG_DAEMON
+	if loggingAsync != nil {
+		loggingAsync.close()
+		loggingAsync = nil
+	}
+	asyncDropped.Store(0)
+	loggingTotalSize = nil
+	loggingLogDir = nil
+	loggingFileMode = 0
+	loggingFileDefaults = builtinFileDefaults
+	loggingFileUsesDefaults = false
+	loggingOptionsSet = false
+	errorLogger = nil
+	errorTotalSize = nil
+	loggingColor = colorAuto
+	loggingHooks = nil
+	loggingRecordSink = nil
+	loggingFilters = nil
+	loggingFilterPanic = false
+	loggingCollapseBlankLines = false
+	loggingMultiline = multilineRaw
+	loggingRing = nil
+	loggingCapture = nil
+	loggingLevelStyle = levelStyleFull
+	loggingWriteErrorHandler = warnWriteError
+	loggingMaxFields = defaultMaxFields
+	writeErrorWarned.Store(false)
+	nowFunc = time.Now
+	loggingComponentLevels = nil
+	loggingSamplers = nil
+	if loggingTimeRotation != nil {
+		loggingTimeRotation.close()
+		loggingTimeRotation = nil
+	}
+	detectStderrTerminal()
+	panicStackSize = defaultPanicStackSize
+	panicFormat = PanicFormat{}
 	logger = &lumberjack.Logger{}
 }
+
+func init() {
+	reset()
+}
@@ -16,78 +16,142 @@
 package logging
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	testutils "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/testing"
 	"gopkg.in/natefinch/lumberjack.v2"
 
 	. "github.com/onsi/ginkgo/v2"
-	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega"
 )
 
+// writeRecorder records each Write call it receives
+type writeRecorder struct {
+	writes []string
+}
+
+func (w *writeRecorder) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+// recordLogs resets logging to record the lines of level and below, without
+// timestamps, in the returned writeRecorder
+func recordLogs(level Level) *writeRecorder {
+	Reset()
+	loggingStderr = false
+	w := &writeRecorder{}
+	loggingW = w
+	loggingLevel.Store(level)
+	SetTimestamp(false)
+	return w
+}
+
+// failingWriter fails every Write
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func panicFromHelper() {
+	Panicf("foobar")
+}
+
 func TestLogging(t *testing.T) {
-	RegisterFailHandler(Fail)
+	gomega.RegisterFailHandler(Fail)
 	RunSpecs(t, "Logging")
 }
 
 var _ = Describe("logging operations", func() {
 
 	BeforeEach(func() {
+		Reset()
 		loggingStderr = false
-		loggingW = nil
-		loggingLevel = PanicLevel
 	})
 
 	It("Check file setter with empty", func() {
-		SetLogFile("")
-		Expect(loggingW).To(BeNil())
+		gomega.Expect(SetLogFile("")).To(gomega.Succeed())
+		gomega.Expect(loggingW).To(gomega.BeNil())
 	})
 
 	It("Check file setter with empty", func() {
-		SetLogFile("/tmp/foobar.logging")
-		Expect(loggingW).NotTo(Equal(nil))
-		// check file existence
+		gomega.Expect(SetLogFile("/tmp/foobar.logging")).To(gomega.Succeed())
+		gomega.Expect(loggingW).NotTo(gomega.Equal(nil))
+		gomega.Expect("/tmp/foobar.logging").To(gomega.BeAnExistingFile())
 	})
 
 	It("Check file setter with bad filepath", func() {
-		SetLogFile("/invalid/filepath")
-		Expect(loggingW).NotTo(Equal(nil))
-		// check file existence
+		err := SetLogFile("/invalid/filepath")
+		gomega.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("failed to open log file /invalid/filepath")))
+		gomega.Expect(loggingW).To(gomega.BeNil())
+		gomega.Expect(logger.Filename).To(gomega.BeEmpty())
 	})
 
 	It("Check loglevel setter", func() {
 		SetLogLevel("debug")
-		Expect(loggingLevel).To(Equal(DebugLevel))
-		Expect(loggingLevel.String()).To(Equal("debug"))
+		gomega.Expect(loggingLevel.Load()).To(gomega.Equal(DebugLevel))
+		gomega.Expect(loggingLevel.Load().String()).To(gomega.Equal("debug"))
 		SetLogLevel("Error")
-		Expect(loggingLevel).To(Equal(ErrorLevel))
-		Expect(loggingLevel.String()).To(Equal("error"))
+		gomega.Expect(loggingLevel.Load()).To(gomega.Equal(ErrorLevel))
+		gomega.Expect(loggingLevel.Load().String()).To(gomega.Equal("error"))
 		SetLogLevel("VERbose")
-		Expect(loggingLevel).To(Equal(VerboseLevel))
-		Expect(loggingLevel.String()).To(Equal("verbose"))
+		gomega.Expect(loggingLevel.Load()).To(gomega.Equal(VerboseLevel))
+		gomega.Expect(loggingLevel.Load().String()).To(gomega.Equal("verbose"))
+		SetLogLevel("warning")
+		gomega.Expect(loggingLevel.Load()).To(gomega.Equal(WarningLevel))
+		gomega.Expect(loggingLevel.Load().String()).To(gomega.Equal("warning"))
+		SetLogLevel("WARN")
+		gomega.Expect(loggingLevel.Load()).To(gomega.Equal(WarningLevel))
 		SetLogLevel("PANIC")
-		Expect(loggingLevel).To(Equal(PanicLevel))
-		Expect(loggingLevel.String()).To(Equal("panic"))
+		gomega.Expect(loggingLevel.Load()).To(gomega.Equal(PanicLevel))
+		gomega.Expect(loggingLevel.Load().String()).To(gomega.Equal("panic"))
 	})
 
 	It("Check loglevel setter with invalid level", func() {
-		currentLevel := loggingLevel
+		currentLevel := loggingLevel.Load()
 		SetLogLevel("XXXX")
-		Expect(loggingLevel).To(Equal(currentLevel))
+		gomega.Expect(loggingLevel.Load()).To(gomega.Equal(currentLevel))
+	})
+
+	It("Check an invalid level is reported to the log destinations", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		SetTimestamp(false)
+		SetLogCaller(true)
+		err := SetLogLevel("XXXX")
+		var unknown *UnknownLevelError
+		gomega.Expect(errors.As(err, &unknown)).To(gomega.BeTrue())
+		gomega.Expect(unknown.Level).To(gomega.Equal("XXXX"))
+		gomega.Expect(loggingLevel.Load()).To(gomega.Equal(PanicLevel))
+		gomega.Expect(SetLogLevel("debug")).To(gomega.Succeed())
+		gomega.Expect(w.writes).To(gomega.HaveLen(1))
+		gomega.Expect(w.writes[0]).To(gomega.MatchRegexp(`^\[warning\] logging_test\.go:\d+ multus logging: cannot set logging level to XXXX\n$`))
+		loggingW = nil
 	})
 
 	It("Check log to stderr setter with invalid level", func() {
 		currentVal := loggingStderr
 		SetLogStderr(!currentVal)
-		Expect(loggingStderr).NotTo(Equal(currentVal))
+		gomega.Expect(loggingStderr).NotTo(gomega.Equal(currentVal))
 	})
 
 	It("Check log function is worked", func() {
 		Debugf("foobar")
 		Verbosef("foobar")
-		Expect(Errorf("foobar")).NotTo(BeNil())
+		Warningf("foobar")
+		gomega.Expect(Errorf("foobar")).NotTo(gomega.BeNil())
 		Panicf("foobar")
 	})
 
@@ -95,21 +159,21 @@ var _ = Describe("logging operations", func() {
 		SetLogStderr(true)
 		Debugf("foobar")
 		Verbosef("foobar")
-		Expect(Errorf("foobar")).NotTo(BeNil())
+		Warningf("foobar")
+		gomega.Expect(Errorf("foobar")).NotTo(gomega.BeNil())
 		Panicf("foobar")
 	})
 
 	It("Check log function is worked with stderr", func() {
 		tmpDir, err := os.MkdirTemp("", "multus_tmp")
-		SetLogFile(fmt.Sprintf("%s/log.txt", tmpDir))
+		gomega.Expect(SetLogFile(fmt.Sprintf("%s/log.txt", tmpDir))).To(gomega.Succeed())
 		Debugf("foobar")
 		Verbosef("foobar")
-		Expect(Errorf("foobar")).NotTo(BeNil())
+		gomega.Expect(Errorf("foobar")).NotTo(gomega.BeNil())
 		Panicf("foobar")
-		logger.Filename = ""
 		loggingW = nil
 		err = os.RemoveAll(tmpDir)
-		Expect(err).NotTo(HaveOccurred())
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		// Revert the log variable to init
 		loggingW = nil
 		logger = &lumberjack.Logger{}
@@ -117,14 +181,15 @@ var _ = Describe("logging operations", func() {
 
 	// Tests public getter
 	It("Check getter for logging level with current level", func() {
-		currentLevel := loggingLevel
-		Expect(currentLevel).To(Equal(GetLoggingLevel()))
+		currentLevel := loggingLevel.Load()
+		gomega.Expect(currentLevel).To(gomega.Equal(GetLoggingLevel()))
 	})
 
 	It("Check user settings logOptions for logging", func() {
-		SetLogFile("/var/log/multus.log")
+		logFile := filepath.Join(GinkgoT().TempDir(), "multus.log")
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
 		expectLogger := &lumberjack.Logger{
-			Filename:   "/var/log/multus.log",
+			Filename:   logFile,
 			MaxAge:     1,
 			MaxSize:    10,
 			MaxBackups: 1,
@@ -136,14 +201,15 @@ var _ = Describe("logging operations", func() {
 			MaxBackups: testutils.Int(1),
 			Compress:   testutils.Bool(true),
 		}
-		SetLogOptions(logOptions)
-		Expect(expectLogger).To(Equal(logger))
+		gomega.Expect(SetLogOptions(logOptions)).To(gomega.Succeed())
+		gomega.Expect(expectLogger).To(gomega.Equal(logger))
 	})
 
 	It("Check user settings logOptions and missing some options", func() {
-		SetLogFile("/var/log/multus.log")
+		logFile := filepath.Join(GinkgoT().TempDir(), "multus.log")
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
 		expectLogger := &lumberjack.Logger{
-			Filename:   "/var/log/multus.log",
+			Filename:   logFile,
 			MaxAge:     5,
 			MaxSize:    100,
 			MaxBackups: 1,
@@ -153,21 +219,1043 @@ var _ = Describe("logging operations", func() {
 			MaxBackups: testutils.Int(1),
 			Compress:   testutils.Bool(true),
 		}
-		SetLogOptions(logOptions)
-		Expect(expectLogger).To(Equal(logger))
+		gomega.Expect(SetLogOptions(logOptions)).To(gomega.Succeed())
+		gomega.Expect(expectLogger).To(gomega.Equal(logger))
 	})
 
 	It("Check user don't settings logOptions for logging", func() {
-		SetLogFile("/var/log/multus.log")
+		logFile := filepath.Join(GinkgoT().TempDir(), "multus.log")
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
 		logger1 := &lumberjack.Logger{
-			Filename:   "/var/log/multus.log",
+			Filename:   logFile,
 			MaxAge:     5,
 			MaxSize:    100,
 			MaxBackups: 5,
 			Compress:   true,
 		}
-		SetLogOptions(nil)
-		Expect(logger1).To(Equal(logger))
+		gomega.Expect(SetLogOptions(nil)).To(gomega.Succeed())
+		gomega.Expect(logger1).To(gomega.Equal(logger))
+	})
+
+	It("Check warning level ordering", func() {
+		gomega.Expect(ErrorLevel < WarningLevel).To(gomega.BeTrue())
+		gomega.Expect(WarningLevel < VerboseLevel).To(gomega.BeTrue())
+		gomega.Expect(WarningLevel < MaxLevel).To(gomega.BeTrue())
+	})
+
+	It("Check log format setter", func() {
+		gomega.Expect(SetLogFormat("JSON")).To(gomega.Succeed())
+		gomega.Expect(loggingFormat).To(gomega.Equal(jsonFormat))
+		gomega.Expect(SetLogFormat("text")).To(gomega.Succeed())
+		gomega.Expect(loggingFormat).To(gomega.Equal(textFormat))
+		gomega.Expect(SetLogFormat("xml")).NotTo(gomega.Succeed())
+		gomega.Expect(loggingFormat).To(gomega.Equal(textFormat))
+	})
+
+	It("Check the schema version is only in json log lines", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		loggingLevel.Store(DebugLevel)
+		SetTimestamp(false)
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		WithField("schemaVersion", "x").Debugf("foobar")
+		SetSchemaVersion("multus-1")
+		Debugf("foobar")
+		SetSchemaVersion("")
+		gomega.Expect(SetLogFormat("text")).To(gomega.Succeed())
+		Debugf("foobar")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			`{"level":"debug","msg":"foobar","fields.schemaVersion":"x","schemaVersion":"1"}` + "\n",
+			`{"level":"debug","msg":"foobar","schemaVersion":"multus-1"}` + "\n",
+			"[debug] foobar\n",
+		}))
+		gomega.Expect(loggingSchemaVersion).To(gomega.Equal(SchemaVersion))
+		loggingW = nil
+	})
+
+	It("Check log function is worked with json format", func() {
+		tmpDir, err := os.MkdirTemp("", "multus_tmp")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		gomega.Expect(SetLogFile(fmt.Sprintf("%s/log.txt", tmpDir))).To(gomega.Succeed())
+		SetLogLevel("debug")
+		Debugf("foo [%s]\nbar", "baz")
+
+		data, err := os.ReadFile(fmt.Sprintf("%s/log.txt", tmpDir))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		record := map[string]string{}
+		gomega.Expect(json.Unmarshal(data, &record)).To(gomega.Succeed())
+		gomega.Expect(record).To(gomega.HaveKeyWithValue("level", "debug"))
+		gomega.Expect(record).To(gomega.HaveKeyWithValue("msg", "foo [baz]\nbar"))
+		gomega.Expect(record).To(gomega.HaveKey("time"))
+
+		err = os.RemoveAll(tmpDir)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		// Revert the log variable to init
+		loggingW = nil
+		logger = &lumberjack.Logger{}
+	})
+
+	It("Check json strings are encoded as encoding/json does", func() {
+		for _, s := range []string{"", "plain text", "quote \" and \\", "tab\tnew\nline", "ünïcode", "\xff"} {
+			var buf bytes.Buffer
+			appendJSONValue(&buf, s)
+			expected, err := json.Marshal(s)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(buf.String()).To(gomega.Equal(string(expected)))
+		}
+	})
+
+	It("Check log function is safe for concurrent use", func() {
+		tmpDir, err := os.MkdirTemp("", "multus_tmp")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					Debugf("goroutine %d line %d", i, j)
+					Verbosef("goroutine %d line %d", i, j)
+					_ = GetLoggingLevel()
+				}
+			}(i)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				SetLogLevel("debug")
+				_ = SetLogFile(fmt.Sprintf("%s/log.txt", tmpDir))
+				_ = SetLogOptions(nil)
+				_ = SetLogFormat("json")
+				SetLogLevel("error")
+				_ = SetLogFormat("text")
+			}
+		}()
+		wg.Wait()
+
+		err = os.RemoveAll(tmpDir)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		// Revert the log variable to init
+		loggingW = nil
+		logger = &lumberjack.Logger{}
+	})
+
+	It("Check log line is emitted in a single write", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		loggingLevel.Store(DebugLevel)
+		Debugf("foo %s", "bar")
+		gomega.Expect(w.writes).To(gomega.HaveLen(1))
+		gomega.Expect(w.writes[0]).To(gomega.HaveSuffix("[debug] foo bar\n"))
+
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		Verbosef("foo %s", "bar")
+		gomega.Expect(w.writes).To(gomega.HaveLen(2))
+		gomega.Expect(w.writes[1]).To(gomega.HaveSuffix("\"msg\":\"foo bar\",\"schemaVersion\":\"1\"}\n"))
+		loggingW = nil
+	})
+
+	It("Check fatal log is always emitted and exits", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		exitCode := -1
+		exitFunc = func(code int) { exitCode = code }
+		defer func() { exitFunc = os.Exit }()
+
+		Fatalf("foo %s", "bar")
+		gomega.Expect(exitCode).To(gomega.Equal(1))
+		gomega.Expect(w.writes).To(gomega.HaveLen(1))
+		gomega.Expect(w.writes[0]).To(gomega.HaveSuffix("[fatal] foo bar\n"))
+		loggingW = nil
+	})
+
+	It("Check the level numbers round-trip", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		SetTimestamp(false)
+		gomega.Expect(SetLevelStyle("numeric")).To(gomega.Succeed())
+		for level := PanicLevel; level < MaxLevel; level++ {
+			n := strconv.Itoa(int(level))
+			parsed, err := ParseLevel(n)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(parsed).To(gomega.Equal(level))
+			var decoded Level
+			gomega.Expect(json.Unmarshal([]byte(n), &decoded)).To(gomega.Succeed())
+			gomega.Expect(decoded).To(gomega.Equal(level))
+
+			SetLogLevelNum(int(level))
+			gomega.Expect(GetLoggingLevel()).To(gomega.Equal(level))
+			gomega.Expect(ParseLevel(strconv.Itoa(int(GetLoggingLevel())))).To(gomega.Equal(level))
+			gomega.Expect(V(int(level))).To(gomega.BeTrue())
+			gomega.Expect(V(int(level) + 1)).To(gomega.Equal(level == TraceLevel))
+
+			printf(nil, level, "foobar")
+			gomega.Expect(w.writes[len(w.writes)-1]).To(gomega.Equal("[" + n + "] foobar\n"))
+		}
+		_, err := ParseLevel(strconv.Itoa(int(MaxLevel)))
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		loggingW = nil
+	})
+
+	It("Check level parser", func() {
+		level, err := ParseLevel("Debug")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(level).To(gomega.Equal(DebugLevel))
+		level, err = ParseLevel("warn")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(level).To(gomega.Equal(WarningLevel))
+		level, err = ParseLevel("trace")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(level).To(gomega.Equal(TraceLevel))
+		level, err = ParseLevel("info")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(level).To(gomega.Equal(VerboseLevel))
+
+		level, err = ParseLevel("XXXX")
+		gomega.Expect(level).To(gomega.Equal(UnknownLevel))
+		var levelErr *UnknownLevelError
+		gomega.Expect(errors.As(err, &levelErr)).To(gomega.BeTrue())
+		gomega.Expect(levelErr.Level).To(gomega.Equal("XXXX"))
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("trace, debug, verbose, warning, error, panic"))
+	})
+
+	It("Check Infof and Tracef", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		SetTimestamp(false)
+		SetLogLevel("info")
+		Infof("info")
+		Tracef("filtered")
+		SetLogLevel("trace")
+		Tracef("trace")
+		Named("component").Tracef("trace")
+		Named("component").Infof("info")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			"[verbose] info\n",
+			"[trace] trace\n",
+			"[trace] [component] trace\n",
+			"[verbose] [component] info\n",
+		}))
+	})
+
+	It("Check caller is added to log messages", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		loggingLevel.Store(VerboseLevel)
+		SetLogCaller(true)
+		Verbosef("foobar")
+		Warningf("foobar")
+		gomega.Expect(Errorf("foobar")).NotTo(gomega.BeNil())
+		Debugf("foobar")
+		gomega.Expect(w.writes).To(gomega.HaveLen(3))
+		for _, line := range w.writes {
+			gomega.Expect(line).To(gomega.MatchRegexp(`\] logging_test\.go:\d+ foobar\n$`))
+		}
+
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		Verbosef("foobar")
+		gomega.Expect(w.writes[3]).To(gomega.MatchRegexp(`"caller":"logging_test\.go:\d+"`))
+		loggingW = nil
+	})
+
+	It("Check the host name and the pid are added to log messages", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		loggingLevel.Store(VerboseLevel)
+		SetTimestamp(false)
+		host, err := os.Hostname()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		pid := os.Getpid()
+
+		SetIncludeHostname(true)
+		SetIncludePID(true)
+		WithField("ifname", "net1").Verbosef("foobar")
+		gomega.Expect(w.writes[0]).To(gomega.Equal(fmt.Sprintf("[verbose] foobar ifname=net1 host=%s pid=%d\n", host, pid)))
+
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		WithField("host", "other").Verbosef("foobar")
+		gomega.Expect(w.writes[1]).To(gomega.Equal(fmt.Sprintf(`{"level":"verbose","msg":"foobar","fields.host":"other","host":%q,"pid":%d,"schemaVersion":"1"}`+"\n", host, pid)))
+
+		SetIncludeHostname(false)
+		SetIncludePID(false)
+		WithField("host", "other").Verbosef("foobar")
+		gomega.Expect(w.writes[2]).To(gomega.Equal(`{"level":"verbose","msg":"foobar","host":"other","schemaVersion":"1"}` + "\n"))
+		loggingW = nil
+	})
+
+	It("Check panic prints the stack trace", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		panicFromHelper()
+		gomega.Expect(w.writes).To(gomega.HaveLen(4))
+		gomega.Expect(w.writes[1]).To(gomega.HaveSuffix("========= Stack trace output ========\n"))
+		gomega.Expect(w.writes[2]).To(gomega.ContainSubstring("goroutine "))
+		gomega.Expect(w.writes[2]).To(gomega.ContainSubstring("logging.panicFromHelper"))
+		gomega.Expect(w.writes[3]).To(gomega.HaveSuffix("========= Stack trace output end ========\n"))
+		loggingW = nil
+	})
+
+	It("Check panic format without banners and with limited frames", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		SetTimestamp(false)
+		SetPanicFormat(PanicFormat{NoBanners: true, MaxFrames: 1})
+		panicFromHelper()
+		gomega.Expect(w.writes).To(gomega.HaveLen(2))
+		gomega.Expect(w.writes[0]).To(gomega.Equal("[panic] foobar\n"))
+		lines := strings.Split(strings.TrimSuffix(w.writes[1], "\n"), "\n")
+		gomega.Expect(lines).To(gomega.HaveLen(3))
+		gomega.Expect(lines[0]).To(gomega.MatchRegexp(`^\[panic\] goroutine \d+ \[running\]:$`))
+		gomega.Expect(lines[1]).To(gomega.ContainSubstring("logging.panicFromHelper"))
+		gomega.Expect(lines[2]).To(gomega.ContainSubstring("logging_test.go:"))
+
+		SetPanicFormat(PanicFormat{})
+		panicFromHelper()
+		gomega.Expect(w.writes).To(gomega.HaveLen(6))
+		gomega.Expect(w.writes[3]).To(gomega.Equal("[panic] ========= Stack trace output ========\n"))
+		loggingW = nil
+	})
+
+	It("Check panic stack size setter", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		SetPanicStackSize(32)
+		gomega.Expect(panicStackSize).To(gomega.Equal(32))
+		panicFromHelper()
+		gomega.Expect(w.writes).To(gomega.HaveLen(4))
+		gomega.Expect(len(w.writes[2])).To(gomega.BeNumerically("<", len("2006-01-02T15:04:05Z07:00 [panic] \n")+32))
+		SetPanicStackSize(0)
+		gomega.Expect(panicStackSize).To(gomega.Equal(defaultPanicStackSize))
+		loggingW = nil
+	})
+
+	It("Check error wrapping", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		loggingLevel.Store(ErrorLevel)
+		cause := errors.New("cause")
+
+		err := Errorf("failed: %w", cause)
+		gomega.Expect(errors.Is(err, cause)).To(gomega.BeTrue())
+		gomega.Expect(w.writes[0]).To(gomega.HaveSuffix("[error] failed: cause\n"))
+
+		err = WrapError(cause, "failed to %s", "foo")
+		gomega.Expect(errors.Is(err, cause)).To(gomega.BeTrue())
+		gomega.Expect(err.Error()).To(gomega.Equal("failed to foo: cause"))
+		gomega.Expect(w.writes[1]).To(gomega.HaveSuffix("[error] failed to foo: cause\n"))
+		loggingW = nil
+	})
+
+	It("Check writer getter", func() {
+		gomega.Expect(Writer()).To(gomega.Equal(io.Discard))
+		w := &writeRecorder{}
+		loggingW = w
+		gomega.Expect(Writer()).To(gomega.Equal(w))
+		loggingW = nil
+	})
+
+	It("Check output setter", func() {
+		var buf bytes.Buffer
+		loggingLevel.Store(DebugLevel)
+		SetOutput(&buf)
+		Debugf("foobar")
+		gomega.Expect(buf.String()).To(gomega.HaveSuffix("[debug] foobar\n"))
+
+		SetOutput(nil)
+		gomega.Expect(loggingW).To(gomega.BeNil())
+		Debugf("foobar")
+
+		ResetOutput()
+		gomega.Expect(loggingW).To(gomega.BeNil())
+		gomega.Expect(SetLogFile("/tmp/foobar.logging")).To(gomega.Succeed())
+		SetOutput(&buf)
+		ResetOutput()
+		gomega.Expect(loggingW).To(gomega.Equal(logger))
+		loggingW = nil
+		logger = &lumberjack.Logger{}
+	})
+
+	It("Check reset restores the initial state", func() {
+		SetLogStderr(false)
+		SetLogLevel("debug")
+		gomega.Expect(SetLogFile("/tmp/foobar.logging")).To(gomega.Succeed())
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		SetLogCaller(true)
+		SetPanicStackSize(32)
+
+		Reset()
+		gomega.Expect(loggingStderr).To(gomega.BeTrue())
+		gomega.Expect(loggingW).To(gomega.BeNil())
+		gomega.Expect(loggingLevel.Load()).To(gomega.Equal(PanicLevel))
+		gomega.Expect(loggingFormat).To(gomega.Equal(textFormat))
+		gomega.Expect(loggingCaller).To(gomega.BeFalse())
+		gomega.Expect(panicStackSize).To(gomega.Equal(defaultPanicStackSize))
+		gomega.Expect(logger).To(gomega.Equal(&lumberjack.Logger{}))
+	})
+
+	It("Check log line is written to all outputs", func() {
+		var buf1, buf2 bytes.Buffer
+		loggingLevel.Store(DebugLevel)
+		AddOutput(&buf1)
+		AddOutput(failingWriter{})
+		AddOutput(&buf2)
+		AddOutput(nil)
+		gomega.Expect(loggingOutputs).To(gomega.HaveLen(3))
+		Debugf("foobar")
+		gomega.Expect(buf1.String()).To(gomega.HaveSuffix("[debug] foobar\n"))
+		gomega.Expect(buf2.String()).To(gomega.Equal(buf1.String()))
+
+		ClearOutputs()
+		gomega.Expect(loggingOutputs).To(gomega.BeEmpty())
+	})
+
+	It("Check timestamp can be disabled", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		loggingLevel.Store(DebugLevel)
+		Debugf("foobar")
+		gomega.Expect(w.writes[0]).To(gomega.MatchRegexp(`^\d{4}-\d{2}-\d{2}T\S+ \[debug\] foobar\n$`))
+
+		SetTimestamp(false)
+		Debugf("foobar")
+		gomega.Expect(w.writes[1]).To(gomega.Equal("[debug] foobar\n"))
+
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		Debugf("foobar")
+		gomega.Expect(w.writes[2]).To(gomega.Equal(`{"level":"debug","msg":"foobar","schemaVersion":"1"}` + "\n"))
+		loggingW = nil
+	})
+
+	It("Check timestamp format setter", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		loggingLevel.Store(DebugLevel)
+		SetTimestampFormat("2006/01/02 15:04:05.000")
+		Debugf("foobar")
+		gomega.Expect(w.writes[0]).To(gomega.MatchRegexp(`^\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}\.\d{3} \[debug\] foobar\n$`))
+
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		Debugf("foobar")
+		gomega.Expect(w.writes[1]).To(gomega.MatchRegexp(`"time":"\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}\.\d{3}"`))
+
+		SetTimestampFormat("")
+		gomega.Expect(loggingTimestampFormat).To(gomega.Equal(time.RFC3339))
+		loggingW = nil
+	})
+
+	It("Check UTC setter", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		loggingLevel.Store(DebugLevel)
+		nowFunc = func() time.Time {
+			return time.Date(2023, 1, 2, 3, 4, 5, 0, time.FixedZone("UTC+1", 3600))
+		}
+		defer func() { nowFunc = time.Now }()
+
+		Debugf("foobar")
+		gomega.Expect(w.writes[0]).To(gomega.HavePrefix("2023-01-02T03:04:05+01:00 "))
+
+		SetUTC(true)
+		gomega.Expect(logger.LocalTime).To(gomega.BeFalse())
+		Debugf("foobar")
+		gomega.Expect(w.writes[1]).To(gomega.HavePrefix("2023-01-02T02:04:05Z "))
+
+		SetUTC(false)
+		gomega.Expect(logger.LocalTime).To(gomega.BeTrue())
+		gomega.Expect(loggingW).To(gomega.Equal(w))
+		loggingW = nil
+	})
+
+	It("Check backup names use local time when enabled", func() {
+		local := time.Local
+		time.Local = time.FixedZone("UTC+5", 5*3600)
+		defer func() { time.Local = local }()
+		tmpDir := GinkgoT().TempDir()
+		// no backups to remove or compress, so that lumberjack does not read
+		// time.Local in the background
+		gomega.Expect(SetLogOptions(&LogOptions{MaxAge: testutils.Int(0), MaxBackups: testutils.Int(0), Compress: testutils.Bool(false)})).To(gomega.Succeed())
+		gomega.Expect(SetLogFile(filepath.Join(tmpDir, "multus.log"))).To(gomega.Succeed())
+
+		SetLocalTime(true)
+		gomega.Expect(logger.LocalTime).To(gomega.BeTrue())
+		gomega.Expect(loggingUTC).To(gomega.BeFalse())
+		gomega.Expect(Rotate()).To(gomega.Succeed())
+		backups, err := filepath.Glob(filepath.Join(tmpDir, "multus-*.log"))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(backups).To(gomega.HaveLen(1))
+		ts := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(backups[0]), "multus-"), ".log")
+		t, err := time.ParseInLocation("2006-01-02T15-04-05.000", ts, time.Local)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(t).To(gomega.BeTemporally("~", time.Now(), time.Minute))
+
+		SetLocalTime(false)
+		gomega.Expect(logger.LocalTime).To(gomega.BeFalse())
+		gomega.Expect(loggingUTC).To(gomega.BeTrue())
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	It("Check the timestamp precision on stderr and the log file", func() {
+		r, pw, err := os.Pipe()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		stderr := os.Stderr
+		os.Stderr = pw
+		defer func() {
+			os.Stderr = stderr
+			r.Close()
+		}()
+		logFile := filepath.Join(GinkgoT().TempDir(), "multus.log")
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
+		SetLogStderr(true)
+		SetLogLevel("verbose")
+		SetUTC(true)
+
+		SetTimestampPrecision(MillisecondPrecision)
+		gomega.Expect(loggingTimestampFormat).To(gomega.Equal("2006-01-02T15:04:05.000Z07:00"))
+		Verbosef("millisecond")
+		SetTimestampPrecision(Precision(10))
+		gomega.Expect(loggingTimestampFormat).To(gomega.Equal("2006-01-02T15:04:05.000Z07:00"))
+		SetTimestampPrecision(NanosecondPrecision)
+		Verbosef("nanosecond")
+		SetTimestampPrecision(SecondPrecision)
+		gomega.Expect(loggingTimestampFormat).To(gomega.Equal(time.RFC3339))
+		Verbosef("second")
+
+		pw.Close()
+		out, err := io.ReadAll(r)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		data, err := os.ReadFile(logFile)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		for _, lines := range []string{string(out), string(data)} {
+			gomega.Expect(lines).To(gomega.MatchRegexp(`(?m)^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{3}Z \[verbose\] millisecond$`))
+			gomega.Expect(lines).To(gomega.MatchRegexp(`(?m)^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{9}Z \[verbose\] nanosecond$`))
+			gomega.Expect(lines).To(gomega.MatchRegexp(`(?m)^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z \[verbose\] second$`))
+		}
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	It("Check the clock setter freezes the timestamps", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		loggingLevel.Store(DebugLevel)
+		SetUTC(true)
+		SetClock(func() time.Time {
+			return time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+		})
+		Debugf("foobar")
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		Debugf("foobar")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			"2023-01-02T03:04:05Z [debug] foobar\n",
+			`{"time":"2023-01-02T03:04:05Z","level":"debug","msg":"foobar","schemaVersion":"1"}` + "\n",
+		}))
+
+		SetClock(nil)
+		gomega.Expect(nowFunc().Year()).NotTo(gomega.Equal(2023))
+		SetClock(func() time.Time { return time.Time{} })
+		Reset()
+		gomega.Expect(nowFunc().IsZero()).To(gomega.BeFalse())
+	})
+
+	It("Check filtered out messages do not read the clock", func() {
+		loggingW = &writeRecorder{}
+		loggingLevel.Store(ErrorLevel)
+		calls := 0
+		nowFunc = func() time.Time {
+			calls++
+			return time.Now()
+		}
+		defer func() { nowFunc = time.Now }()
+
+		Debugf("foobar")
+		Debugfn(func() string { return "foobar" })
+		gomega.Expect(calls).To(gomega.Equal(0))
+		_ = Errorf("foobar")
+		gomega.Expect(calls).To(gomega.Equal(1))
+		loggingW = nil
+	})
+
+	It("Check user settings logOptions with negative values", func() {
+		logFile := filepath.Join(GinkgoT().TempDir(), "multus.log")
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
+		gomega.Expect(SetLogOptions(nil)).To(gomega.Succeed())
+		expectLogger := &lumberjack.Logger{
+			Filename:   logFile,
+			MaxAge:     5,
+			MaxSize:    100,
+			MaxBackups: 5,
+			Compress:   true,
+		}
+
+		err := SetLogOptions(&LogOptions{MaxAge: testutils.Int(-1)})
+		gomega.Expect(err).To(gomega.MatchError("invalid log option maxAge: -1, must not be negative"))
+		err = SetLogOptions(&LogOptions{MaxSize: testutils.Int(-1)})
+		gomega.Expect(err).To(gomega.MatchError("invalid log option maxSize: -1, must not be negative"))
+		err = SetLogOptions(&LogOptions{MaxBackups: testutils.Int(-5), MaxSize: testutils.Int(10)})
+		gomega.Expect(err).To(gomega.MatchError("invalid log option maxBackups: -5, must not be negative"))
+		gomega.Expect(expectLogger).To(gomega.Equal(logger))
+
+		gomega.Expect(SetLogOptions(&LogOptions{MaxAge: testutils.Int(0)})).To(gomega.Succeed())
+		gomega.Expect(logger.MaxAge).To(gomega.Equal(0))
+	})
+
+	It("Check SetLogOptions and SetLogFile with a nil logger", func() {
+		logger = nil
+		gomega.Expect(SetLogOptions(nil)).To(gomega.Succeed())
+		gomega.Expect(logger).To(gomega.Equal(&lumberjack.Logger{
+			MaxAge:     5,
+			MaxSize:    100,
+			MaxBackups: 5,
+			Compress:   true,
+		}))
+
+		logFile := filepath.Join(GinkgoT().TempDir(), "multus.log")
+		logger = nil
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
+		gomega.Expect(logger).To(gomega.Equal(&lumberjack.Logger{Filename: logFile}))
+
+		logger = nil
+		ResetOutput()
+		gomega.Expect(loggingW).To(gomega.BeNil())
+
+		logger = nil
+		SetUTC(true)
+		gomega.Expect(logger.LocalTime).To(gomega.BeFalse())
+	})
+
+	It("Check the getters of the logging configuration", func() {
+		gomega.Expect(GetLogFile()).To(gomega.BeEmpty())
+		gomega.Expect(GetLogStderr()).To(gomega.BeFalse())
+
+		logFile := filepath.Join(GinkgoT().TempDir(), "multus.log")
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
+		gomega.Expect(SetLogOptions(&LogOptions{MaxAge: testutils.Int(1), Compress: testutils.Bool(false)})).To(gomega.Succeed())
+		SetLogStderr(true)
+
+		gomega.Expect(GetLogFile()).To(gomega.Equal(logFile))
+		gomega.Expect(GetLogStderr()).To(gomega.BeTrue())
+		options := GetLogOptions()
+		gomega.Expect(options).To(gomega.Equal(LogOptions{
+			MaxAge:       testutils.Int(1),
+			MaxSize:      testutils.Int(100),
+			MaxBackups:   testutils.Int(5),
+			Compress:     testutils.Bool(false),
+			MaxTotalSize: testutils.Int(0),
+		}))
+
+		// the returned options are a copy
+		*options.MaxAge = 10
+		gomega.Expect(logger.MaxAge).To(gomega.Equal(1))
+		gomega.Expect(*GetLogOptions().MaxAge).To(gomega.Equal(1))
+	})
+
+	It("Check Enabled against the logging level", func() {
+		loggingW = io.Discard
+		SetLogLevel("verbose")
+		gomega.Expect(Enabled(ErrorLevel)).To(gomega.BeTrue())
+		gomega.Expect(Enabled(VerboseLevel)).To(gomega.BeTrue())
+		gomega.Expect(Enabled(FatalLevel)).To(gomega.BeTrue())
+		gomega.Expect(VerboseEnabled()).To(gomega.BeTrue())
+		gomega.Expect(DebugEnabled()).To(gomega.BeFalse())
+
+		SetLogLevel("debug")
+		gomega.Expect(DebugEnabled()).To(gomega.BeTrue())
+
+		SetLogLevel("panic")
+		gomega.Expect(Enabled(ErrorLevel)).To(gomega.BeFalse())
+		gomega.Expect(VerboseEnabled()).To(gomega.BeFalse())
+		gomega.Expect(Enabled(FatalLevel)).To(gomega.BeTrue())
+		loggingW = nil
+	})
+
+	It("Check Enabled when all outputs are disabled", func() {
+		SetLogLevel("debug")
+		gomega.Expect(Enabled(ErrorLevel)).To(gomega.BeFalse())
+		gomega.Expect(Enabled(FatalLevel)).To(gomega.BeFalse())
+
+		called := 0
+		Debugfn(func() string {
+			called++
+			return "foobar"
+		})
+		gomega.Expect(called).To(gomega.Equal(0))
+		gomega.Expect(Errorf("foobar")).To(gomega.MatchError("foobar"))
+
+		SetLogStderr(true)
+		gomega.Expect(DebugEnabled()).To(gomega.BeTrue())
+		SetLogStderr(false)
+		gomega.Expect(DebugEnabled()).To(gomega.BeFalse())
+		EnableRingBuffer(10)
+		gomega.Expect(DebugEnabled()).To(gomega.BeTrue())
+		EnableRingBuffer(0)
+		RegisterHook(func(Level, string) {})
+		gomega.Expect(DebugEnabled()).To(gomega.BeTrue())
+	})
+
+	It("Check the lazy logging functions", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		SetTimestamp(false)
+		SetLogLevel("error")
+		called := 0
+		msg := func() string {
+			called++
+			return "lazy message"
+		}
+
+		Debugfn(msg)
+		Verbosefn(msg)
+		gomega.Expect(called).To(gomega.Equal(0))
+		gomega.Expect(w.writes).To(gomega.BeEmpty())
+
+		Errorfn(msg)
+		gomega.Expect(called).To(gomega.Equal(1))
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[error] lazy message\n"}))
+
+		SetLogLevel("debug")
+		SetLogCaller(true)
+		Debugfn(msg)
+		gomega.Expect(called).To(gomega.Equal(2))
+		gomega.Expect(w.writes[1]).To(gomega.MatchRegexp(`^\[debug\] logging_test\.go:\d+ lazy message\n$`))
+	})
+
+	It("Check the numeric logging level", func() {
+		for n, level := range []Level{PanicLevel, ErrorLevel, WarningLevel, VerboseLevel, DebugLevel, TraceLevel} {
+			SetLogLevelNum(n)
+			gomega.Expect(GetLoggingLevel()).To(gomega.Equal(level))
+		}
+
+		SetLogLevelNum(-1)
+		gomega.Expect(GetLoggingLevel()).To(gomega.Equal(PanicLevel))
+		SetLogLevelNum(10)
+		gomega.Expect(GetLoggingLevel()).To(gomega.Equal(TraceLevel))
+
+		loggingW = io.Discard
+		SetLogLevel("verbose")
+		gomega.Expect(V(-1)).To(gomega.BeTrue())
+		gomega.Expect(V(0)).To(gomega.BeTrue())
+		gomega.Expect(V(3)).To(gomega.BeTrue())
+		gomega.Expect(V(4)).To(gomega.BeFalse())
+		gomega.Expect(V(10)).To(gomega.BeFalse())
+		loggingW = nil
+	})
+
+	It("Check WithLevel restores the logging level, even on panic", func() {
+		SetLogLevel("error")
+		func() {
+			defer WithLevel(DebugLevel)()
+			gomega.Expect(GetLoggingLevel()).To(gomega.Equal(DebugLevel))
+		}()
+		gomega.Expect(GetLoggingLevel()).To(gomega.Equal(ErrorLevel))
+
+		gomega.Expect(func() {
+			defer WithLevel(TraceLevel)()
+			panic("foobar")
+		}).To(gomega.PanicWith("foobar"))
+		gomega.Expect(GetLoggingLevel()).To(gomega.Equal(ErrorLevel))
+
+		restore := WithLevel(VerboseLevel)
+		restore()
+		SetLogLevel("debug")
+		restore()
+		gomega.Expect(GetLoggingLevel()).To(gomega.Equal(DebugLevel))
+
+		WithLevel(MaxLevel)()
+		gomega.Expect(GetLoggingLevel()).To(gomega.Equal(DebugLevel))
+	})
+
+	It("Check Close closes the log file and restores the defaults", func() {
+		logFile := filepath.Join(GinkgoT().TempDir(), "multus.log")
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
+		SetLogLevel("verbose")
+		EnableAsync(10)
+		Verbosef("before close")
+
+		gomega.Expect(Close()).To(gomega.Succeed())
+		gomega.Expect(os.ReadFile(logFile)).To(gomega.ContainSubstring("before close"))
+		gomega.Expect(loggingAsync).To(gomega.BeNil())
+		gomega.Expect(GetLogFile()).To(gomega.BeEmpty())
+		gomega.Expect(GetLoggingLevel()).To(gomega.Equal(PanicLevel))
+
+		// Close is idempotent
+		gomega.Expect(Close()).To(gomega.Succeed())
+		gomega.Expect(Flush()).To(gomega.Succeed())
+	})
+
+	It("Check Rotate creates a backup of the log file", func() {
+		gomega.Expect(Rotate()).To(gomega.MatchError("cannot rotate the log file: log file is not set"))
+
+		tmpDir := GinkgoT().TempDir()
+		// the backup is not compressed in the background, so that it is
+		// there to read and the temporary directory can be removed
+		gomega.Expect(SetLogOptions(&LogOptions{Compress: testutils.Bool(false)})).To(gomega.Succeed())
+		gomega.Expect(SetLogFile(filepath.Join(tmpDir, "multus.log"))).To(gomega.Succeed())
+		SetLogLevel("verbose")
+		Verbosef("before rotation")
+		gomega.Expect(Rotate()).To(gomega.Succeed())
+		Verbosef("after rotation")
+
+		backups, err := filepath.Glob(filepath.Join(tmpDir, "multus-*.log"))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(backups).To(gomega.HaveLen(1))
+		gomega.Expect(os.ReadFile(backups[0])).To(gomega.ContainSubstring("before rotation"))
+		gomega.Expect(os.ReadFile(filepath.Join(tmpDir, "multus.log"))).To(gomega.ContainSubstring("after rotation"))
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	It("Check the file mode of the log file", func() {
+		tmpDir := GinkgoT().TempDir()
+		logFile := filepath.Join(tmpDir, "multus.log")
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
+		gomega.Expect(GetLogOptions().FileMode).To(gomega.BeNil())
+
+		fileMode := "0600"
+		gomega.Expect(SetLogOptions(&LogOptions{FileMode: &fileMode})).To(gomega.Succeed())
+		gomega.Expect(*GetLogOptions().FileMode).To(gomega.Equal("0600"))
+		info, err := os.Stat(logFile)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(info.Mode().Perm()).To(gomega.Equal(os.FileMode(0600)))
+
+		// rotated log files keep the mode
+		SetLogLevel("verbose")
+		Verbosef("before rotation")
+		gomega.Expect(Rotate()).To(gomega.Succeed())
+		info, err = os.Stat(logFile)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(info.Mode().Perm()).To(gomega.Equal(os.FileMode(0600)))
+
+		// a new log file is created with the mode
+		otherFile := filepath.Join(tmpDir, "other.log")
+		gomega.Expect(SetLogFile(otherFile)).To(gomega.Succeed())
+		info, err = os.Stat(otherFile)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(info.Mode().Perm()).To(gomega.Equal(os.FileMode(0600)))
+
+		for _, mode := range []string{"rw", "0800", "1777", ""} {
+			mode := mode
+			gomega.Expect(SetLogOptions(&LogOptions{FileMode: &mode})).To(gomega.MatchError(gomega.ContainSubstring("invalid log option fileMode")))
+		}
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	It("Check logging to stdout, independently of stderr", func() {
+		r, w, err := os.Pipe()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		defer r.Close()
+		stdout := os.Stdout
+		os.Stdout = w
+		defer func() { os.Stdout = stdout }()
+
+		recorder := &writeRecorder{}
+		loggingW = recorder
+		SetTimestamp(false)
+		SetLogLevel("error")
+		gomega.Expect(GetLogStdout()).To(gomega.BeFalse())
+		_ = Errorf("not on stdout")
+		SetLogStdout(true)
+		gomega.Expect(GetLogStdout()).To(gomega.BeTrue())
+		gomega.Expect(GetLogStderr()).To(gomega.BeFalse())
+		_ = Errorf("on stdout")
+
+		w.Close()
+		out, err := io.ReadAll(r)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(string(out)).To(gomega.Equal("[error] on stdout\n"))
+		gomega.Expect(recorder.writes).To(gomega.HaveLen(2))
+	})
+
+	It("Check levels round-trip through JSON", func() {
+		for _, level := range []Level{PanicLevel, ErrorLevel, WarningLevel, VerboseLevel, DebugLevel, TraceLevel, FatalLevel} {
+			data, err := json.Marshal(level)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(string(data)).To(gomega.Equal(`"` + level.String() + `"`))
+
+			var decoded Level
+			gomega.Expect(json.Unmarshal(data, &decoded)).To(gomega.Succeed())
+			gomega.Expect(decoded).To(gomega.Equal(level))
+		}
+
+		var conf struct {
+			LogLevel Level `json:"logLevel"`
+		}
+		gomega.Expect(json.Unmarshal([]byte(`{"logLevel": "info"}`), &conf)).To(gomega.Succeed())
+		gomega.Expect(conf.LogLevel).To(gomega.Equal(InfoLevel))
+		gomega.Expect(json.Unmarshal([]byte(`{"logLevel": "chatty"}`), &conf)).To(gomega.MatchError(gomega.ContainSubstring(`unknown logging level "chatty"`)))
+		gomega.Expect(json.Unmarshal([]byte(`{"logLevel": 3}`), &conf)).To(gomega.Succeed())
+		gomega.Expect(conf.LogLevel).To(gomega.Equal(VerboseLevel))
+		gomega.Expect(json.Unmarshal([]byte(`{"logLevel": 6}`), &conf)).To(gomega.MatchError(gomega.ContainSubstring("logging level must be a name or a number from 0 to 5")))
+
+		_, err := json.Marshal(UnknownLevel)
+		gomega.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("cannot marshal unknown logging level 7")))
+	})
+
+	It("Check a line allocates no more than benchmarkPrintf measured", func() {
+		if raceEnabled {
+			Skip("the race detector changes the allocations")
+		}
+		loggingStderr = false
+		loggingW = io.Discard
+		loggingLevel.Store(DebugLevel)
+		for format, allocs := range map[logFormat]float64{textFormat: 2, jsonFormat: 8} {
+			loggingFormat = format
+			gomega.Expect(testing.AllocsPerRun(100, func() {
+				Debugf("foo %s %d", "bar", 42)
+			})).To(gomega.BeNumerically("<=", allocs), "format %d", format)
+		}
+	})
+
+	It("Check levels round-trip through text", func() {
+		for _, level := range []Level{PanicLevel, ErrorLevel, WarningLevel, VerboseLevel, DebugLevel, TraceLevel, FatalLevel} {
+			text, err := level.MarshalText()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(string(text)).To(gomega.Equal(level.String()))
+
+			var decoded Level
+			gomega.Expect(decoded.UnmarshalText(text)).To(gomega.Succeed())
+			gomega.Expect(decoded).To(gomega.Equal(level))
+		}
+
+		var level Level
+		gomega.Expect(level.UnmarshalText([]byte("WARN"))).To(gomega.Succeed())
+		gomega.Expect(level).To(gomega.Equal(WarningLevel))
+		gomega.Expect(level.UnmarshalText([]byte("unknown"))).To(gomega.MatchError(gomega.ContainSubstring(`unknown logging level "unknown"`)))
+		gomega.Expect(level).To(gomega.Equal(WarningLevel))
+
+		_, err := UnknownLevel.MarshalText()
+		gomega.Expect(err).To(gomega.MatchError("cannot marshal unknown logging level 7"))
+		_, err = MaxLevel.MarshalText()
+		gomega.Expect(err).To(gomega.HaveOccurred())
+	})
+
+	It("Check Logf with a level chosen at run time", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		SetTimestamp(false)
+		SetLogLevel("warning")
+		exitFunc = func(code int) { Fail("Logf must not exit") }
+		defer func() { exitFunc = os.Exit }()
+
+		Logf(WarningLevel, "warning %d", 1)
+		Logf(VerboseLevel, "filtered")
+		Logf(UnknownLevel, "unknown")
+		Logf(MaxLevel, "max")
+		Logf(FatalLevel, "fatal")
+		Named("component").Logf(ErrorLevel, "error")
+		Named("component").Logf(UnknownLevel, "unknown")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			"[warning] warning 1\n",
+			"[fatal] fatal\n",
+			"[error] [component] error\n",
+		}))
+	})
+
+	It("Check Raw logs the message without format expansion", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		SetTimestamp(false)
+		SetLogLevel("verbose")
+
+		Raw(VerboseLevel, "100% done: %s %d")
+		Raw(DebugLevel, "filtered %s")
+		Raw(UnknownLevel, "unknown %d")
+		Named("exec").Raw(WarningLevel, "output %s %d")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			"[verbose] 100% done: %s %d\n",
+			"[warning] [exec] output %s %d\n",
+		}))
+	})
+
+	It("Check the message functions do not expand format verbs", func() {
+		w := &writeRecorder{}
+		loggingW = w
+		SetTimestamp(false)
+		SetLogLevel("debug")
+
+		Trace("filtered %s")
+		Debug("debug %s")
+		Verbose("verbose %d")
+		Info("info %v")
+		Warning("warning %%")
+		err := Error("pod %s not found: 50%d")
+		gomega.Expect(err).To(gomega.MatchError("pod %s not found: 50%d"))
+		gomega.Expect(Named("cache").Error("error %w")).To(gomega.MatchError("error %w"))
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			"[debug] debug %s\n",
+			"[verbose] verbose %d\n",
+			"[verbose] info %v\n",
+			"[warning] warning %%\n",
+			"[error] pod %s not found: 50%d\n",
+			"[error] [cache] error %w\n",
+		}))
 	})
 
 })
+
+func benchmarkCaller(b *testing.B, enable bool) {
+	loggingStderr = false
+	loggingW = io.Discard
+	loggingLevel.Store(DebugLevel)
+	loggingCaller = enable
+	defer func() {
+		loggingW = nil
+		loggingLevel.Store(PanicLevel)
+		loggingCaller = false
+	}()
+	for i := 0; i < b.N; i++ {
+		Debugf("foo %s", "bar")
+	}
+}
+
+func BenchmarkCallerDisabled(b *testing.B) {
+	benchmarkCaller(b, false)
+}
+
+func BenchmarkCallerEnabled(b *testing.B) {
+	benchmarkCaller(b, true)
+}
+
+// benchmarkPrintf measures a line written to a discarded log file.
+// Measured with -benchmem, before and after pooling the line buffers:
+//
+//	BenchmarkPrintf      before: 248 B/op, 5 allocs/op   after: 112 B/op, 2 allocs/op
+//	BenchmarkPrintfJSON  before: 1240 B/op, 24 allocs/op after: 208 B/op, 8 allocs/op
+//
+// The allocations after are checked by a test as well
+func benchmarkPrintf(b *testing.B, format logFormat) {
+	Reset()
+	loggingStderr = false
+	loggingW = io.Discard
+	loggingLevel.Store(DebugLevel)
+	loggingFormat = format
+	defer Reset()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Debugf("foo %s %d", "bar", 42)
+	}
+}
+
+func BenchmarkPrintf(b *testing.B) {
+	benchmarkPrintf(b, textFormat)
+}
+
+// BenchmarkPrintfFiltered measures a line below the logging level, which
+// must not allocate nor read the clock
+func BenchmarkPrintfFiltered(b *testing.B) {
+	Reset()
+	loggingStderr = false
+	loggingW = io.Discard
+	loggingLevel.Store(ErrorLevel)
+	defer Reset()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Debugf("foo %s %d", "bar", 42)
+	}
+}
+
+func BenchmarkPrintfJSON(b *testing.B) {
+	benchmarkPrintf(b, jsonFormat)
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+)
+
+// logrSink is a logr.LogSink logging with this package
+type logrSink struct {
+	entry *LogEntry
+}
+
+// NewLogrSink returns a logr.LogSink logging with this package, so that
+// logr.New(logging.NewLogrSink()) honors the logging level and destinations.
+// Info verbosity V(0) maps to Error level, V(1) to Verbose level and higher
+// verbosities to Debug level. WithName sets the component, as for Named, and
+// WithValues adds fields
+func NewLogrSink() logr.LogSink {
+	return &logrSink{entry: &LogEntry{}}
+}
+
+// logrLevel converts a logr verbosity into a Level
+func logrLevel(level int) Level {
+	switch {
+	case level <= 0:
+		return ErrorLevel
+	case level == 1:
+		return VerboseLevel
+	}
+	return DebugLevel
+}
+
+// kvFields converts alternating keys and values into fields. A key without
+// value is kept under the !BADKV key
+func kvFields(keysAndValues []interface{}) map[string]interface{} {
+	fields := make(map[string]interface{}, len(keysAndValues)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 == len(keysAndValues) {
+			fields["!BADKV"] = keysAndValues[i]
+			break
+		}
+		fields[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+	}
+	return fields
+}
+
+// Init receives the call depth added by logr
+func (s *logrSink) Init(info logr.RuntimeInfo) {
+	s.entry.callDepth += info.CallDepth
+}
+
+// Enabled reports whether messages of the verbosity are logged
+func (s *logrSink) Enabled(level int) bool {
+	return Enabled(logrLevel(level))
+}
+
+// Info logs a message of the verbosity
+func (s *logrSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	printf(s.entry.WithFields(kvFields(keysAndValues)), logrLevel(level), "%s", msg)
+}
+
+// Error logs an error message, with err as error field
+func (s *logrSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	fields := kvFields(keysAndValues)
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	printf(s.entry.WithFields(fields), ErrorLevel, "%s", msg)
+}
+
+// WithValues returns a sink adding the keys and values to log messages
+func (s *logrSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &logrSink{entry: s.entry.WithFields(kvFields(keysAndValues))}
+}
+
+// WithName returns a sink appending name to the component
+func (s *logrSink) WithName(name string) logr.LogSink {
+	entry := *s.entry
+	if entry.component != "" {
+		name = entry.component + "/" + name
+	}
+	entry.component = name
+	return &logrSink{entry: &entry}
+}
+
+// WithCallDepth returns a sink skipping depth more stack frames for the caller
+func (s *logrSink) WithCallDepth(depth int) logr.LogSink {
+	entry := *s.entry
+	entry.callDepth += depth
+	return &logrSink{entry: &entry}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"errors"
+
+	"github.com/go-logr/logr"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging logr sink", func() {
+
+	var w *writeRecorder
+
+	BeforeEach(func() {
+		w = recordLogs(VerboseLevel)
+	})
+
+	It("Check logr verbosities are mapped and filtered", func() {
+		logger := logr.New(NewLogrSink())
+		logger.Info("foobar")
+		logger.V(1).Info("foobar")
+		logger.V(2).Info("foobar")
+		logger.Error(errors.New("cause"), "foobar")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			"[error] foobar\n",
+			"[verbose] foobar\n",
+			"[error] foobar error=cause\n",
+		}))
+		gomega.Expect(logger.V(2).Enabled()).To(gomega.BeFalse())
+	})
+
+	It("Check logr names and values", func() {
+		logger := logr.New(NewLogrSink()).WithName("controller").WithName("nad").WithValues("namespace", "default")
+		logger.V(1).Info("reconciling", "name", "foo", "dangling")
+		gomega.Expect(w.writes[0]).To(gomega.Equal("[verbose] [controller/nad] reconciling !BADKV=dangling name=foo namespace=default\n"))
+	})
+
+	It("Check logr caller is the logging code", func() {
+		SetLogCaller(true)
+		logr.New(NewLogrSink()).V(1).Info("foobar")
+		gomega.Expect(w.writes[0]).To(gomega.MatchRegexp(`^\[verbose\] logr_test\.go:\d+ foobar\n$`))
+	})
+})
@@ -0,0 +1,45 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/logging"
+)
+
+var linesTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "multus_log_lines_total",
+		Help: "Number of log lines written, by level",
+	},
+	[]string{"level"},
+)
+
+var once sync.Once
+
+// Collector returns the collector of the multus_log_lines_total counter, and
+// starts counting the log lines written. Lines are counted once they pass the
+// logging level filter, so lines of disabled levels are not counted
+func Collector() prometheus.Collector {
+	once.Do(func() {
+		logging.SetLineCounter(func(level logging.Level) {
+			linesTotal.WithLabelValues(level.String()).Inc()
+		})
+	})
+	return linesTotal
+}
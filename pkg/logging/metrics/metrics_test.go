@@ -0,0 +1,49 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/logging"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("logging metrics", func() {
+
+	It("Check log lines are counted by level", func() {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(Collector())
+		logging.SetLogStderr(false)
+		logging.SetLogLevel("verbose")
+
+		logging.Verbosef("foobar")
+		logging.Verbosef("foobar")
+		logging.Debugf("foobar")
+		Expect(logging.Errorf("foobar")).To(HaveOccurred())
+
+		families, err := registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(families).To(HaveLen(1))
+		Expect(families[0].GetName()).To(Equal("multus_log_lines_total"))
+		counts := map[string]float64{}
+		for _, m := range families[0].GetMetric() {
+			counts[m.GetLabel()[0].GetValue()] = m.GetCounter().GetValue()
+		}
+		Expect(counts).To(Equal(map[string]float64{"verbose": 2, "error": 1}))
+	})
+})
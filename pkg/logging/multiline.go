@@ -0,0 +1,79 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// multilineMode type
+type multilineMode uint32
+
+// multilineRaw...multilineEscape indicates how the messages of several lines
+// are written in the text and syslog formats
+const (
+	multilineRaw multilineMode = iota
+	multilinePrefix
+	multilineEscape
+)
+
+// SetMultilineMode sets how the messages of several lines, e.g. a marshaled
+// NetConf or a stack trace, are written in the text and syslog formats: "raw"
+// writes them as is, the default, "prefix" repeats the header of the log
+// line, timestamp and level, on each of their lines, and "escape" replaces
+// their newlines with \n so that the log line is a single line. The json
+// format always escapes them
+func SetMultilineMode(mode string) error {
+	var m multilineMode
+	switch strings.ToLower(mode) {
+	case "raw":
+		m = multilineRaw
+	case "prefix":
+		m = multilinePrefix
+	case "escape":
+		m = multilineEscape
+	default:
+		return fmt.Errorf("unknown multiline mode %q, accepted modes are: raw, prefix, escape", mode)
+	}
+
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingMultiline = m
+	return nil
+}
+
+// appendMessage appends msg to the log line in buf, which holds the header
+// of the line so far, by the multiline mode. The caller must hold
+// loggingMutex
+func appendMessage(buf *bytes.Buffer, msg string) {
+	if loggingMultiline == multilineRaw || !strings.Contains(msg, "\n") {
+		buf.WriteString(msg)
+		return
+	}
+	if loggingMultiline == multilineEscape {
+		buf.WriteString(strings.ReplaceAll(msg, "\n", `\n`))
+		return
+	}
+	header := append([]byte(nil), buf.Bytes()...)
+	for i, line := range strings.Split(msg, "\n") {
+		if i > 0 {
+			buf.WriteByte('\n')
+			buf.Write(header)
+		}
+		buf.WriteString(line)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging multiline messages", func() {
+	var w *writeRecorder
+
+	BeforeEach(func() {
+		w = recordLogs(VerboseLevel)
+		SetTimestamp(true)
+		now := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+		SetClock(func() time.Time { return now })
+	})
+
+	It("Check raw mode writes the message as is", func() {
+		Named("conf").WithField("a", 1).Verbosef("netconf:\n{\n}")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"2023-05-01T10:00:00Z [verbose] [conf] netconf:\n{\n} a=1\n"}))
+	})
+
+	It("Check prefix mode repeats the header on each line", func() {
+		gomega.Expect(SetMultilineMode("prefix")).To(gomega.Succeed())
+		Named("conf").WithField("a", 1).Verbosef("netconf:\n{\n}")
+		Verbosef("single line")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			"2023-05-01T10:00:00Z [verbose] [conf] netconf:\n" +
+				"2023-05-01T10:00:00Z [verbose] [conf] {\n" +
+				"2023-05-01T10:00:00Z [verbose] [conf] } a=1\n",
+			"2023-05-01T10:00:00Z [verbose] single line\n",
+		}))
+	})
+
+	It("Check escape mode writes a single line", func() {
+		gomega.Expect(SetMultilineMode("Escape")).To(gomega.Succeed())
+		Verbosef("netconf:\n{\n}")
+		gomega.Expect(SetLogFormat("syslog")).To(gomega.Succeed())
+		SetTimestamp(false)
+		Verbosef("a\nb")
+		gomega.Expect(w.writes[0]).To(gomega.Equal(`2023-05-01T10:00:00Z [verbose] netconf:\n{\n}` + "\n"))
+		gomega.Expect(w.writes[1]).To(gomega.HaveSuffix(` - - a\nb` + "\n"))
+	})
+
+	It("Check an unknown multiline mode returns an error", func() {
+		gomega.Expect(SetMultilineMode("fold")).To(gomega.MatchError(`unknown multiline mode "fold", accepted modes are: raw, prefix, escape`))
+	})
+})
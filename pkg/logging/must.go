@@ -0,0 +1,34 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+// MustSetLogFile sets the log file as SetLogFile, and logs the error with
+// Panicf then panics with it when that fails, for the configure or die
+// pattern of main functions
+func MustSetLogFile(filename string) {
+	if err := SetLogFile(filename); err != nil {
+		Panicf("multus logging: %v", err)
+		panic(err)
+	}
+}
+
+// MustSetLogOptions sets the log options as SetLogOptions, and logs the error
+// with Panicf then panics with it when that fails
+func MustSetLogOptions(opts *LogOptions) {
+	if err := SetLogOptions(opts); err != nil {
+		Panicf("multus logging: %v", err)
+		panic(err)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"path/filepath"
+
+	testutils "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging must setters", func() {
+
+	var w *writeRecorder
+
+	BeforeEach(func() {
+		w = recordLogs(PanicLevel)
+	})
+
+	AfterEach(func() {
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	It("Check the must setters succeed", func() {
+		logFile := filepath.Join(GinkgoT().TempDir(), "multus.log")
+		gomega.Expect(func() { MustSetLogFile(logFile) }).NotTo(gomega.Panic())
+		gomega.Expect(func() { MustSetLogOptions(&LogOptions{MaxAge: testutils.Int(1)}) }).NotTo(gomega.Panic())
+		gomega.Expect(logger.Filename).To(gomega.Equal(logFile))
+		gomega.Expect(logger.MaxAge).To(gomega.Equal(1))
+	})
+
+	It("Check the must setters log and panic on errors", func() {
+		gomega.Expect(func() { MustSetLogFile("/invalid/filepath") }).To(gomega.PanicWith(gomega.MatchError(gomega.ContainSubstring("failed to open log file /invalid/filepath"))))
+		gomega.Expect(w.writes[0]).To(gomega.HavePrefix("[panic] multus logging: failed to open log file /invalid/filepath"))
+
+		gomega.Expect(func() { MustSetLogOptions(&LogOptions{MaxAge: testutils.Int(-1)}) }).To(gomega.PanicWith(gomega.MatchError("invalid log option maxAge: -1, must not be negative")))
+		gomega.Expect(w.writes).To(gomega.ContainElement("[panic] multus logging: invalid log option maxAge: -1, must not be negative\n"))
+	})
+})
@@ -0,0 +1,65 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import "errors"
+
+// ConfigureFromNetConf configures logging from the logging settings of a
+// NetConf: logToStderr, logOptions, logFile and logLevel. An empty file or
+// level keeps the current one, and a nil opts sets the default options as
+// SetLogOptions does. The setters are applied
+// in the order they require, which callers of the individual setters must
+// follow as well:
+//
+//  1. SetLogStderr
+//  2. SetLogOptions, before SetLogFile, so that SetLogFile keeps the rotation
+//     options rather than applying the defaults
+//  3. SetLogFile
+//  4. SetLogLevel, last, so that the level problems are logged to the log file
+//
+// All of them are applied even when one fails, and the errors are returned
+// joined
+func ConfigureFromNetConf(level string, file string, stderr bool, opts *LogOptions) error {
+	if opts == nil {
+		opts = &LogOptions{}
+	}
+	return configure(&stderr, opts, file, level)
+}
+
+// configure applies the logging settings in the order of
+// ConfigureFromNetConf. A nil stderr or opts keeps the current setting, as
+// does an empty file or level
+func configure(stderr *bool, opts *LogOptions, file string, level string) error {
+	var errs []error
+	if stderr != nil {
+		SetLogStderr(*stderr)
+	}
+	if opts != nil {
+		if err := SetLogOptions(opts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if file != "" {
+		if err := SetLogFile(file); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if level != "" {
+		if err := SetLogLevel(level); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"path/filepath"
+
+	testutils "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging configuration from the netconf", func() {
+	var logFile string
+
+	BeforeEach(func() {
+		Reset()
+		logFile = filepath.Join(GinkgoT().TempDir(), "multus.log")
+	})
+
+	AfterEach(func() {
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	It("Check the file keeps the options set with it", func() {
+		gomega.Expect(ConfigureFromNetConf("debug", logFile, false, &LogOptions{
+			MaxAge:     testutils.Int(1),
+			MaxSize:    testutils.Int(10),
+			MaxBackups: testutils.Int(2),
+			Compress:   testutils.Bool(false),
+		})).To(gomega.Succeed())
+		gomega.Expect(loggingStderr).To(gomega.BeFalse())
+		gomega.Expect(loggingLevel.Load()).To(gomega.Equal(DebugLevel))
+		gomega.Expect(logger.Filename).To(gomega.Equal(logFile))
+		gomega.Expect(logger.MaxAge).To(gomega.Equal(1))
+		gomega.Expect(logger.MaxSize).To(gomega.Equal(10))
+		gomega.Expect(logger.MaxBackups).To(gomega.Equal(2))
+		gomega.Expect(logger.Compress).To(gomega.BeFalse())
+	})
+
+	It("Check an empty file and level keep the current ones", func() {
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
+		SetLogLevel("error")
+		gomega.Expect(ConfigureFromNetConf("", "", true, nil)).To(gomega.Succeed())
+		gomega.Expect(loggingStderr).To(gomega.BeTrue())
+		gomega.Expect(loggingLevel.Load()).To(gomega.Equal(ErrorLevel))
+		gomega.Expect(logger.Filename).To(gomega.Equal(logFile))
+	})
+
+	It("Check the errors are joined and the other settings applied", func() {
+		err := ConfigureFromNetConf("loud", filepath.Join(logFile, "bad", "multus.log"), false, &LogOptions{
+			MaxAge:  testutils.Int(-1),
+			MaxSize: testutils.Int(10),
+		})
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("maxAge"))
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("bad/multus.log"))
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("loud"))
+		gomega.Expect(loggingStderr).To(gomega.BeFalse())
+	})
+})
@@ -0,0 +1,47 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"regexp"
+	"strings"
+)
+
+// blankLines matches the runs of more than one blank line
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// trimMessage strips a trailing newline of msg, as every log line gets one,
+// e.g. for messages of captured command output. With SetCollapseBlankLines,
+// it strips all of the trailing newlines and collapses the runs of blank
+// lines into a single one. The caller must hold loggingMutex
+func trimMessage(msg string) string {
+	if !loggingCollapseBlankLines {
+		return strings.TrimSuffix(msg, "\n")
+	}
+	msg = strings.TrimRight(msg, "\n")
+	if strings.Contains(msg, "\n\n\n") {
+		msg = blankLines.ReplaceAllString(msg, "\n\n")
+	}
+	return msg
+}
+
+// SetCollapseBlankLines sets flag for stripping all of the trailing newlines
+// of messages, rather than a single one, and collapsing their runs of blank
+// lines into a single blank line
+func SetCollapseBlankLines(enable bool) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingCollapseBlankLines = enable
+}
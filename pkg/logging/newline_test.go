@@ -0,0 +1,66 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging message newlines", func() {
+	var w *writeRecorder
+
+	BeforeEach(func() {
+		w = recordLogs(VerboseLevel)
+	})
+
+	It("Check a single trailing newline is stripped", func() {
+		Verbosef("no newline")
+		Verbosef("one newline\n")
+		Verbosef("%s", "two newlines\n\n")
+		Verbosef("three newlines\n\n\n")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			"[verbose] no newline\n",
+			"[verbose] one newline\n",
+			"[verbose] two newlines\n\n",
+			"[verbose] three newlines\n\n\n",
+		}))
+	})
+
+	It("Check the blank lines are collapsed when enabled", func() {
+		SetCollapseBlankLines(true)
+		Verbosef("no newline")
+		Verbosef("one newline\n")
+		Verbosef("two newlines\n\n")
+		Verbosef("output:\nline 1\n\n\n\nline 2\n\nline 3\n\n\n")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			"[verbose] no newline\n",
+			"[verbose] one newline\n",
+			"[verbose] two newlines\n",
+			"[verbose] output:\nline 1\n\nline 2\n\nline 3\n",
+		}))
+	})
+
+	It("Check the hooks and the json format get the stripped message", func() {
+		var msgs []string
+		RegisterHook(func(level Level, msg string) {
+			msgs = append(msgs, msg)
+		})
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		Verbosefn(func() string { return "lazy\n" })
+		gomega.Expect(msgs).To(gomega.Equal([]string{"lazy"}))
+		gomega.Expect(w.writes[0]).To(gomega.ContainSubstring(`"msg":"lazy",`))
+	})
+})
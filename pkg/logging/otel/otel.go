@@ -0,0 +1,142 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+
+	"gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/logging"
+)
+
+// scopeName is the instrumentation scope of the log records
+const scopeName = "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/logging"
+
+// componentAttribute is the attribute of the component of named entries
+const componentAttribute = "component"
+
+// Exporter exports the log lines as OpenTelemetry log records
+type Exporter struct {
+	provider *sdklog.LoggerProvider
+	logger   log.Logger
+}
+
+// NewOTelExporter returns an Exporter converting every log line written to an
+// OTel log record, which is batched and exported by exporter every interval,
+// or the SDK default when interval is 0. The severity is mapped from the
+// level, the fields and the component become attributes, and the record is
+// correlated with the trace of the context of WithContext, if any. The
+// Exporter replaces the record sink of the logging library, until Shutdown
+func NewOTelExporter(exporter sdklog.Exporter, interval time.Duration, opts ...sdklog.LoggerProviderOption) *Exporter {
+	var batchOpts []sdklog.BatchProcessorOption
+	if interval > 0 {
+		batchOpts = append(batchOpts, sdklog.WithExportInterval(interval))
+	}
+	opts = append(opts, sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter, batchOpts...)))
+	provider := sdklog.NewLoggerProvider(opts...)
+	e := &Exporter{
+		provider: provider,
+		logger:   provider.Logger(scopeName),
+	}
+	logging.SetRecordSink(e.emit)
+	return e
+}
+
+// emit converts the log line to a log record and emits it
+func (e *Exporter) emit(r logging.Record) {
+	var rec log.Record
+	rec.SetTimestamp(r.Time)
+	rec.SetSeverity(severity(r.Level))
+	rec.SetSeverityText(r.Level.String())
+	rec.SetBody(log.StringValue(r.Msg))
+	if r.Component != "" {
+		rec.AddAttributes(log.String(componentAttribute, r.Component))
+	}
+	for k, v := range r.Fields {
+		rec.AddAttributes(log.KeyValue{Key: k, Value: value(v)})
+	}
+	e.logger.Emit(r.Context, rec)
+}
+
+// ForceFlush exports the log records which are not exported yet
+func (e *Exporter) ForceFlush(ctx context.Context) error {
+	return e.provider.ForceFlush(ctx)
+}
+
+// Shutdown stops converting the log lines, and exports the remaining log
+// records before shutting the exporter down
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	logging.SetRecordSink(nil)
+	return e.provider.Shutdown(ctx)
+}
+
+// severity returns the OTel severity of level
+func severity(level logging.Level) log.Severity {
+	switch level {
+	case logging.PanicLevel, logging.FatalLevel:
+		return log.SeverityFatal
+	case logging.ErrorLevel:
+		return log.SeverityError
+	case logging.WarningLevel:
+		return log.SeverityWarn
+	case logging.VerboseLevel:
+		return log.SeverityInfo
+	case logging.DebugLevel:
+		return log.SeverityDebug
+	case logging.TraceLevel:
+		return log.SeverityTrace
+	}
+	return log.SeverityUndefined
+}
+
+// value returns the OTel value of a field, the values of other types than
+// the basic ones are formatted as strings
+func value(v interface{}) log.Value {
+	switch v := v.(type) {
+	case string:
+		return log.StringValue(v)
+	case bool:
+		return log.BoolValue(v)
+	case int:
+		return log.IntValue(v)
+	case int32:
+		return log.Int64Value(int64(v))
+	case int64:
+		return log.Int64Value(v)
+	case uint32:
+		return log.Int64Value(int64(v))
+	case float32:
+		return log.Float64Value(float64(v))
+	case float64:
+		return log.Float64Value(v)
+	case []byte:
+		return log.BytesValue(v)
+	case []string:
+		values := make([]log.Value, len(v))
+		for i, s := range v {
+			values[i] = log.StringValue(s)
+		}
+		return log.SliceValue(values...)
+	case error:
+		return log.StringValue(v.Error())
+	case time.Duration:
+		return log.StringValue(v.String())
+	}
+	return log.StringValue(fmt.Sprint(v))
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/trace"
+
+	"gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/logging"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// exported is a log record received by the recordingExporter
+type exported struct {
+	severity     log.Severity
+	severityText string
+	body         string
+	attributes   map[string]string
+	traceID      trace.TraceID
+}
+
+// recordingExporter records the exported log records
+type recordingExporter struct {
+	mu      sync.Mutex
+	records []exported
+}
+
+func (r *recordingExporter) Export(_ context.Context, records []sdklog.Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rec := range records {
+		e := exported{
+			severity:     rec.Severity(),
+			severityText: rec.SeverityText(),
+			body:         rec.Body().AsString(),
+			attributes:   map[string]string{},
+			traceID:      rec.TraceID(),
+		}
+		rec.WalkAttributes(func(kv log.KeyValue) bool {
+			e.attributes[kv.Key] = kv.Value.String()
+			return true
+		})
+		r.records = append(r.records, e)
+	}
+	return nil
+}
+
+func (r *recordingExporter) Shutdown(context.Context) error   { return nil }
+func (r *recordingExporter) ForceFlush(context.Context) error { return nil }
+
+func (r *recordingExporter) exported() []exported {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]exported(nil), r.records...)
+}
+
+var _ = Describe("logging otel exporter", func() {
+	var recorder *recordingExporter
+	var exporter *Exporter
+
+	BeforeEach(func() {
+		logging.Reset()
+		logging.SetLogStderr(false)
+		logging.SetLogLevel("verbose")
+		recorder = &recordingExporter{}
+		exporter = NewOTelExporter(recorder, time.Hour)
+	})
+
+	AfterEach(func() {
+		Expect(exporter.Shutdown(context.Background())).To(Succeed())
+	})
+
+	It("Check log lines are exported as log records", func() {
+		traceID := trace.TraceID{1, 2, 3}
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID,
+			SpanID:  trace.SpanID{4, 5, 6},
+		}))
+
+		logging.Debugf("filtered")
+		logging.Named("cache").WithField("ifname", "net1").WithField("count", 3).WithContext(ctx).Verbosef("attached")
+		Expect(logging.Errorf("failed")).To(HaveOccurred())
+		Expect(recorder.exported()).To(BeEmpty())
+
+		Expect(exporter.ForceFlush(context.Background())).To(Succeed())
+		records := recorder.exported()
+		Expect(records).To(HaveLen(2))
+		Expect(records[0]).To(Equal(exported{
+			severity:     log.SeverityInfo,
+			severityText: "verbose",
+			body:         "attached",
+			attributes:   map[string]string{"component": "cache", "ifname": "net1", "count": "3"},
+			traceID:      traceID,
+		}))
+		Expect(records[1].severity).To(Equal(log.SeverityError))
+		Expect(records[1].body).To(Equal("failed"))
+		Expect(records[1].traceID.IsValid()).To(BeFalse())
+	})
+
+	It("Check redacted fields are masked in the exported records", func() {
+		logging.AddKeyRedactor()
+		logging.WithField("password", "hunter2").Verbosef("logged in")
+		Expect(exporter.ForceFlush(context.Background())).To(Succeed())
+		records := recorder.exported()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].attributes).To(HaveKeyWithValue("password", "***"))
+	})
+
+	It("Check the remaining log records are exported on shutdown", func() {
+		logging.Panicf("panic")
+		Expect(exporter.Shutdown(context.Background())).To(Succeed())
+		logging.Verbosef("after shutdown")
+		records := recorder.exported()
+		Expect(records).NotTo(BeEmpty())
+		Expect(records[0].severity).To(Equal(log.SeverityFatal))
+		Expect(records[0].severityText).To(Equal("panic"))
+		Expect(records[len(records)-1].body).NotTo(Equal("after shutdown"))
+	})
+
+	It("Check log records are exported every interval", func() {
+		Expect(exporter.Shutdown(context.Background())).To(Succeed())
+		exporter = NewOTelExporter(recorder, 10*time.Millisecond)
+		logging.Verbosef("foobar")
+		Eventually(recorder.exported).Should(HaveLen(1))
+	})
+
+	It("Check field values are converted", func() {
+		Expect(value("a").AsString()).To(Equal("a"))
+		Expect(value(true).AsBool()).To(BeTrue())
+		Expect(value(int64(3)).AsInt64()).To(Equal(int64(3)))
+		Expect(value(1.5).AsFloat64()).To(Equal(1.5))
+		Expect(value([]string{"a", "b"}).AsSlice()).To(HaveLen(2))
+		Expect(value(time.Second).AsString()).To(Equal("1s"))
+		Expect(value(struct{ A int }{1}).AsString()).To(Equal("{1}"))
+	})
+})
@@ -0,0 +1,141 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket limiting the rate of log lines
+type rateLimiter struct {
+	mutex      sync.Mutex
+	rate       float64
+	burst      float64
+	tokens     float64
+	last       time.Time
+	suppressed int
+	// report logs the number of lines suppressed, when no line is allowed
+	// within the window after the first one suppressed
+	report func()
+}
+
+func newRateLimiter(perSecond, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   float64(perSecond),
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+// allow reports whether a log line may be written at the given time. Once a
+// line is allowed, it also returns the number of lines suppressed before it
+func (l *rateLimiter) allow(now time.Time) (bool, int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if !l.last.IsZero() && now.After(l.last) {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	if now.After(l.last) {
+		l.last = now
+	}
+	if l.tokens < 1 {
+		l.suppressed++
+		if l.suppressed == 1 && l.report != nil {
+			time.AfterFunc(l.window(), l.report)
+		}
+		return false, 0
+	}
+	l.tokens--
+	suppressed := l.suppressed
+	l.suppressed = 0
+	return true, suppressed
+}
+
+// window returns the time until the next line is allowed. The caller must
+// hold l.mutex
+func (l *rateLimiter) window() time.Duration {
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// takeSuppressed returns the number of lines suppressed since the last line
+// allowed or reported, and resets it
+func (l *rateLimiter) takeSuppressed() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	suppressed := l.suppressed
+	l.suppressed = 0
+	return suppressed
+}
+
+// reportSuppressed logs the number of lines suppressed by the rate limit. The
+// caller must hold loggingMutex
+func reportSuppressed(t time.Time, suppressed int) {
+	output(&record{
+		time:  t,
+		level: WarningLevel,
+		msg:   fmt.Sprintf("%d messages suppressed by rate limiting", suppressed),
+	})
+}
+
+// flushRateLimit logs the number of lines suppressed by the rate limit since
+// the last line allowed, if any. The caller must hold loggingMutex
+func flushRateLimit() {
+	if loggingRateLimiter == nil {
+		return
+	}
+	if suppressed := loggingRateLimiter.takeSuppressed(); suppressed > 0 {
+		reportSuppressed(nowFunc(), suppressed)
+	}
+}
+
+// SetRateLimit limits log lines to perSecond per second, allowing bursts of
+// burst lines. Lines over the limit are dropped, and their number is logged
+// along with the next line allowed, or when no line is allowed within the
+// time a line takes to be allowed again, and by Flush and Close. Lines
+// dropped by sampling (see SetSampling) do not count against the limit. Zero
+// or less perSecond removes the limit
+func SetRateLimit(perSecond, burst int) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	if perSecond <= 0 {
+		loggingRateLimiter = nil
+		return
+	}
+	l := newRateLimiter(perSecond, burst)
+	l.report = func() {
+		loggingMutex.RLock()
+		defer loggingMutex.RUnlock()
+		if loggingRateLimiter == l {
+			flushRateLimit()
+		}
+	}
+	loggingRateLimiter = l
+}
+
+// SetRateLimitBypass sets flag for error and panic messages to bypass the
+// rate limit, enabled by default. Fatal messages always bypass it
+func SetRateLimitBypass(enable bool) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingRateLimitBypass = enable
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging rate limit", func() {
+
+	var w *writeRecorder
+	var now time.Time
+
+	BeforeEach(func() {
+		w = recordLogs(DebugLevel)
+		now = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+		nowFunc = func() time.Time { return now }
+	})
+
+	AfterEach(func() {
+		nowFunc = time.Now
+	})
+
+	It("Check lines over the limit are dropped and counted", func() {
+		SetRateLimit(1, 2)
+		for i := 0; i < 5; i++ {
+			Debugf("line %d", i)
+		}
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[debug] line 0\n", "[debug] line 1\n"}))
+
+		now = now.Add(time.Second)
+		Debugf("line 5")
+		gomega.Expect(w.writes[2:]).To(gomega.Equal([]string{
+			"[warning] 3 messages suppressed by rate limiting\n",
+			"[debug] line 5\n",
+		}))
+	})
+
+	It("Check suppressed lines are counted when no line follows", func() {
+		written := func() []string {
+			loggingMutex.Lock()
+			defer loggingMutex.Unlock()
+			return append([]string(nil), w.writes...)
+		}
+		SetRateLimit(20, 1)
+		for i := 0; i < 3; i++ {
+			Debugf("line %d", i)
+		}
+		gomega.Eventually(written).Should(gomega.Equal([]string{
+			"[debug] line 0\n",
+			"[warning] 2 messages suppressed by rate limiting\n",
+		}))
+
+		Debugf("line 3")
+		gomega.Expect(Flush()).To(gomega.Succeed())
+		gomega.Expect(written()[2:]).To(gomega.Equal([]string{"[warning] 1 messages suppressed by rate limiting\n"}))
+	})
+
+	It("Check errors bypass the limit", func() {
+		SetRateLimit(1, 1)
+		Debugf("foobar")
+		Debugf("foobar")
+		gomega.Expect(Errorf("foobar")).To(gomega.HaveOccurred())
+		gomega.Expect(w.writes).To(gomega.HaveLen(2))
+		gomega.Expect(w.writes[1]).To(gomega.Equal("[error] foobar\n"))
+
+		SetRateLimitBypass(false)
+		gomega.Expect(Errorf("foobar")).To(gomega.HaveOccurred())
+		gomega.Expect(w.writes).To(gomega.HaveLen(2))
+	})
+
+	It("Check rate limit can be removed", func() {
+		SetRateLimit(1, 1)
+		gomega.Expect(loggingRateLimiter).NotTo(gomega.BeNil())
+		SetRateLimit(0, 0)
+		gomega.Expect(loggingRateLimiter).To(gomega.BeNil())
+		for i := 0; i < 5; i++ {
+			Debugf("foobar")
+		}
+		gomega.Expect(w.writes).To(gomega.HaveLen(5))
+	})
+})
@@ -0,0 +1,133 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Record is a log line passed to the record sink
+type Record struct {
+	// Context is the context of WithContext, context.Background() otherwise
+	Context   context.Context
+	Time      time.Time
+	Level     Level
+	Component string
+	Msg       string
+	// Fields are the fields of the log line, redacted as the log line. They
+	// must not be modified
+	Fields map[string]interface{}
+}
+
+// RecordSink is called with every log line written
+type RecordSink func(Record)
+
+// newRecord returns the Record of the log line of e, which msg is redacted
+// already. The caller must hold loggingMutex
+func newRecord(e *LogEntry, level Level, t time.Time, msg string) Record {
+	r := Record{Context: context.Background(), Time: t, Level: level, Msg: msg}
+	if e != nil {
+		r.Component = e.component
+		r.Fields = e.fields
+		if len(loggingRedactors) > 0 {
+			r.Fields = redactFields(e.fields)
+		}
+		if e.ctx != nil {
+			r.Context = e.ctx
+		}
+	}
+	return r
+}
+
+// redactFields returns the fields redacted as they are in a json log line, so
+// that the record sink does not get what the log line hides. The fields are
+// returned as is when no redactor matches, and the caller must hold
+// loggingMutex
+func redactFields(fields map[string]interface{}) map[string]interface{} {
+	var redacted map[string]interface{}
+	for k, v := range fields {
+		var value interface{}
+		if chain, ok := v.(errorChain); ok {
+			value = redactChain(chain)
+		} else {
+			value = redactField(k, v)
+		}
+		if value == nil {
+			continue
+		}
+		if redacted == nil {
+			redacted = make(map[string]interface{}, len(fields))
+			for k, v := range fields {
+				redacted[k] = v
+			}
+		}
+		redacted[k] = value
+	}
+	if redacted == nil {
+		return fields
+	}
+	return redacted
+}
+
+// redactField returns the value of the field key=v redacted, or nil when no
+// redactor matches it
+func redactField(key string, v interface{}) interface{} {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	appendJSONField(&buf, key, v)
+	buf.WriteByte('}')
+	line := redact(buf.Bytes())
+	if bytes.Equal(line, buf.Bytes()) {
+		return nil
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(line, &object); err != nil {
+		return string(line)
+	}
+	return object[key]
+}
+
+// redactChain returns the error chain redacted, or nil when no redactor
+// matches it
+func redactChain(chain errorChain) interface{} {
+	var redacted errorChain
+	for i, s := range chain {
+		r := string(redact([]byte(s)))
+		if r == s {
+			continue
+		}
+		if redacted == nil {
+			redacted = append(errorChain(nil), chain...)
+		}
+		redacted[i] = r
+	}
+	if redacted == nil {
+		return nil
+	}
+	return redacted
+}
+
+// SetRecordSink sets fn to be called with every log line written, with its
+// component and fields, e.g. to export the log lines to another system. fn
+// is called as the hooks, after them, and only one sink is set at a time.
+// nil removes it
+func SetRecordSink(fn RecordSink) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingRecordSink = fn
+}
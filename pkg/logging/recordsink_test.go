@@ -0,0 +1,99 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+type sinkKey struct{}
+
+var _ = Describe("logging record sink", func() {
+	var records []Record
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		loggingLevel.Store(VerboseLevel)
+		records = nil
+		SetRecordSink(func(r Record) {
+			records = append(records, r)
+		})
+	})
+
+	It("Check the sink gets the written lines with their fields", func() {
+		now := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+		SetClock(func() time.Time { return now })
+		ctx := context.WithValue(context.Background(), sinkKey{}, "span")
+
+		Debugf("filtered")
+		Named("cache").WithField("ifname", "net1").WithContext(ctx).Verbosef("attached %s", "net1")
+		_ = Errorf("failed")
+		gomega.Expect(records).To(gomega.HaveLen(2))
+		gomega.Expect(records[0].Time).To(gomega.Equal(now))
+		gomega.Expect(records[0].Level).To(gomega.Equal(VerboseLevel))
+		gomega.Expect(records[0].Component).To(gomega.Equal("cache"))
+		gomega.Expect(records[0].Msg).To(gomega.Equal("attached net1"))
+		gomega.Expect(records[0].Fields).To(gomega.Equal(map[string]interface{}{"ifname": "net1"}))
+		gomega.Expect(records[0].Context.Value(sinkKey{})).To(gomega.Equal("span"))
+		gomega.Expect(records[1].Context).To(gomega.Equal(context.Background()))
+		gomega.Expect(records[1].Fields).To(gomega.BeNil())
+
+		SetRecordSink(nil)
+		_ = Errorf("failed")
+		gomega.Expect(records).To(gomega.HaveLen(2))
+	})
+
+	It("Check the sink gets redacted messages and is a sink itself", func() {
+		gomega.Expect(AddRedactor(regexp.MustCompile(`secret`), "***")).To(gomega.Succeed())
+		Verbosef("the secret")
+		gomega.Expect(records).To(gomega.HaveLen(1))
+		gomega.Expect(records[0].Msg).To(gomega.Equal("the ***"))
+	})
+
+	It("Check the sink gets redacted fields", func() {
+		AddKeyRedactor()
+		gomega.Expect(AddRedactor(regexp.MustCompile(`secret-[a-z]+`), "***")).To(gomega.Succeed())
+		fields := map[string]interface{}{"password": "hunter2", "ifname": "net1", "count": 3}
+		err := fmt.Errorf("dial: %w", errors.New("bad secret-abc"))
+		WithFields(fields).ErrorErr(err, "failed")
+		gomega.Expect(records).To(gomega.HaveLen(1))
+		gomega.Expect(records[0].Fields).To(gomega.HaveKeyWithValue("password", "***"))
+		gomega.Expect(records[0].Fields).To(gomega.HaveKeyWithValue("ifname", "net1"))
+		gomega.Expect(records[0].Fields).To(gomega.HaveKeyWithValue("count", 3))
+		gomega.Expect(records[0].Fields).To(gomega.HaveKeyWithValue("error", "dial: bad ***"))
+		gomega.Expect(records[0].Fields).To(gomega.HaveKeyWithValue(errorChainField, errorChain{"dial: bad ***", "bad ***"}))
+		gomega.Expect(fields).To(gomega.HaveKeyWithValue("password", "hunter2"))
+	})
+
+	It("Check the sink can log", func() {
+		SetRecordSink(func(r Record) {
+			records = append(records, r)
+			if r.Level == ErrorLevel {
+				Verbosef("sink saw %q", r.Msg)
+			}
+		})
+		_ = Errorf("failed")
+		gomega.Expect(records).To(gomega.HaveLen(2))
+		gomega.Expect(records[1].Msg).To(gomega.Equal(`sink saw "failed"`))
+	})
+})
@@ -0,0 +1,78 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// redactor replaces the matches of a pattern in log lines
+type redactor struct {
+	pattern     *regexp.Regexp
+	replacement []byte
+}
+
+// DefaultRedactedKeys are the keys redacted by AddKeyRedactor by default
+var DefaultRedactedKeys = []string{"password", "passwd", "secret", "token", "credentials"}
+
+// AddRedactor registers a pattern replaced in every log line before it is
+// written, as regexp.ReplaceAll does, so replacement may refer to submatches
+// with $1. Redactors are applied in registration order. It returns an error,
+// and registers nothing, when pattern is nil
+func AddRedactor(pattern *regexp.Regexp, replacement string) error {
+	if pattern == nil {
+		return errors.New("redactor pattern must not be nil")
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingRedactors = append(loggingRedactors, redactor{
+		pattern:     pattern,
+		replacement: []byte(replacement),
+	})
+	return nil
+}
+
+// AddKeyRedactor registers a redactor replacing with "***" the string values
+// of the given json keys, e.g. "password":"...", in log lines. Keys match
+// case-insensitively; without keys, DefaultRedactedKeys are redacted
+func AddKeyRedactor(keys ...string) {
+	if len(keys) == 0 {
+		keys = DefaultRedactedKeys
+	}
+	quoted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		quoted = append(quoted, regexp.QuoteMeta(key))
+	}
+	// quotes may be escaped, when the json is itself in a json log line
+	pattern := regexp.MustCompile(fmt.Sprintf(`(\\?"(?i:%s)\\?"\s*:\s*\\?")[^"\\]*(\\?")`, strings.Join(quoted, "|")))
+	_ = AddRedactor(pattern, "${1}***${2}")
+}
+
+// ClearRedactors removes all the registered redactors
+func ClearRedactors() {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingRedactors = nil
+}
+
+func redact(line []byte) []byte {
+	for _, r := range loggingRedactors {
+		line = r.pattern.ReplaceAll(line, r.replacement)
+	}
+	return line
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"io"
+	"regexp"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging redaction", func() {
+
+	var w *writeRecorder
+
+	BeforeEach(func() {
+		w = recordLogs(DebugLevel)
+	})
+
+	It("Check redactors are applied in registration order", func() {
+		gomega.Expect(AddRedactor(regexp.MustCompile(`foo`), "bar")).To(gomega.Succeed())
+		gomega.Expect(AddRedactor(regexp.MustCompile(`bar(\d)`), "baz$1")).To(gomega.Succeed())
+		Debugf("foo1 bar2 qux")
+		gomega.Expect(w.writes[0]).To(gomega.Equal("[debug] baz1 baz2 qux\n"))
+
+		ClearRedactors()
+		Debugf("foo1")
+		gomega.Expect(w.writes[1]).To(gomega.Equal("[debug] foo1\n"))
+	})
+
+	It("Check a nil pattern is rejected", func() {
+		gomega.Expect(AddRedactor(nil, "***")).To(gomega.MatchError("redactor pattern must not be nil"))
+		gomega.Expect(loggingRedactors).To(gomega.BeEmpty())
+		Debugf("foobar")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[debug] foobar\n"}))
+	})
+
+	It("Check key redactor hides secrets", func() {
+		AddKeyRedactor()
+		Debugf(`LoadNetConf: {"name":"net1","Password": "hunter2","token":"abc"}`)
+		gomega.Expect(w.writes[0]).To(gomega.Equal(`[debug] LoadNetConf: {"name":"net1","Password": "***","token":"***"}` + "\n"))
+
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		Debugf(`{"secret":"s3cr3t"}`)
+		gomega.Expect(w.writes[1]).To(gomega.Equal(`{"level":"debug","msg":"{\"secret\":\"***\"}","schemaVersion":"1"}` + "\n"))
+	})
+
+	It("Check key redactor with given keys", func() {
+		AddKeyRedactor("apiKey")
+		Debugf(`{"apiKey":"abc","password":"def"}`)
+		gomega.Expect(w.writes[0]).To(gomega.Equal(`[debug] {"apiKey":"***","password":"def"}` + "\n"))
+	})
+})
+
+func benchmarkRedact(b *testing.B, redact bool) {
+	Reset()
+	loggingStderr = false
+	loggingW = io.Discard
+	loggingLevel.Store(DebugLevel)
+	if redact {
+		AddKeyRedactor()
+	}
+	defer Reset()
+	for i := 0; i < b.N; i++ {
+		Debugf(`LoadNetConf: {"name":"net1","password":"%s"}`, "hunter2")
+	}
+}
+
+func BenchmarkRedactNone(b *testing.B) {
+	benchmarkRedact(b, false)
+}
+
+func BenchmarkRedactKeys(b *testing.B) {
+	benchmarkRedact(b, true)
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"strings"
+	"sync"
+)
+
+// MaxRingBufferSize is the largest number of log lines kept by the ring
+// buffer
+const MaxRingBufferSize = 10000
+
+// ringBuffer keeps the last log lines written. Log lines are added under the
+// read lock of loggingMutex, so it has its own lock
+type ringBuffer struct {
+	mu    sync.Mutex
+	lines []string
+	// next is the index of the next line added, and of the oldest line
+	// once full
+	next int
+	full bool
+}
+
+func newRingBuffer(n int) *ringBuffer {
+	return &ringBuffer{lines: make([]string, n)}
+}
+
+// add adds the line, evicting the oldest line when full
+func (b *ringBuffer) add(line []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lines[b.next] = strings.TrimSuffix(string(line), "\n")
+	b.next++
+	if b.next == len(b.lines) {
+		b.next = 0
+		b.full = true
+	}
+}
+
+// snapshot returns the lines, oldest first
+func (b *ringBuffer) snapshot() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.full {
+		return append([]string(nil), b.lines[:b.next]...)
+	}
+	lines := make([]string, 0, len(b.lines))
+	lines = append(lines, b.lines[b.next:]...)
+	return append(lines, b.lines[:b.next]...)
+}
+
+// EnableRingBuffer keeps the last n log lines written in memory, for
+// RecentLogs. n is capped to MaxRingBufferSize. Changing n keeps the most
+// recent lines that fit, and zero or less disables the ring buffer
+func EnableRingBuffer(n int) {
+	if n > MaxRingBufferSize {
+		n = MaxRingBufferSize
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	if n <= 0 {
+		loggingRing = nil
+		return
+	}
+	ring := newRingBuffer(n)
+	if loggingRing != nil {
+		lines := loggingRing.snapshot()
+		if len(lines) > n {
+			lines = lines[len(lines)-n:]
+		}
+		for _, l := range lines {
+			ring.add([]byte(l))
+		}
+	}
+	loggingRing = ring
+}
+
+// RecentLogs returns the log lines kept by the ring buffer, oldest first,
+// without the trailing newline
+func RecentLogs() []string {
+	loggingMutex.RLock()
+	defer loggingMutex.RUnlock()
+	if loggingRing == nil {
+		return nil
+	}
+	return loggingRing.snapshot()
+}
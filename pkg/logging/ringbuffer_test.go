@@ -0,0 +1,69 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging ring buffer", func() {
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		loggingLevel.Store(VerboseLevel)
+		SetTimestamp(false)
+	})
+
+	It("Check the ring buffer is disabled by default", func() {
+		Verbosef("line")
+		gomega.Expect(RecentLogs()).To(gomega.BeNil())
+	})
+
+	It("Check wraparound evicts the oldest lines", func() {
+		EnableRingBuffer(3)
+		Verbosef("line 1")
+		Verbosef("line 2")
+		gomega.Expect(RecentLogs()).To(gomega.Equal([]string{"[verbose] line 1", "[verbose] line 2"}))
+
+		Verbosef("line 3")
+		Verbosef("line 4")
+		Verbosef("line 5")
+		gomega.Expect(RecentLogs()).To(gomega.Equal([]string{"[verbose] line 3", "[verbose] line 4", "[verbose] line 5"}))
+	})
+
+	It("Check resizing keeps the most recent lines", func() {
+		EnableRingBuffer(4)
+		for _, l := range []string{"a", "b", "c", "d", "e"} {
+			Verbosef("%s", l)
+		}
+		EnableRingBuffer(2)
+		gomega.Expect(RecentLogs()).To(gomega.Equal([]string{"[verbose] d", "[verbose] e"}))
+
+		EnableRingBuffer(3)
+		Verbosef("f")
+		Verbosef("g")
+		gomega.Expect(RecentLogs()).To(gomega.Equal([]string{"[verbose] e", "[verbose] f", "[verbose] g"}))
+
+		EnableRingBuffer(0)
+		gomega.Expect(RecentLogs()).To(gomega.BeNil())
+	})
+
+	It("Check the capacity is bounded", func() {
+		EnableRingBuffer(MaxRingBufferSize + 1)
+		gomega.Expect(loggingRing.lines).To(gomega.HaveLen(MaxRingBufferSize))
+	})
+})
@@ -0,0 +1,100 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"os"
+	"time"
+)
+
+// timeRotation rotates the log file on a schedule, from a background
+// goroutine
+type timeRotation struct {
+	interval time.Duration
+	stop     chan struct{}
+}
+
+// nextRotation returns the first time after t aligned to interval. Times are
+// aligned to the UTC wall clock, e.g. a 24h interval rotates at midnight UTC
+// and a 1h interval on the hour
+func nextRotation(t time.Time, interval time.Duration) time.Time {
+	return t.Truncate(interval).Add(interval)
+}
+
+func newTimeRotation(interval time.Duration) *timeRotation {
+	r := &timeRotation{
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+func (r *timeRotation) run() {
+	for {
+		now := time.Now()
+		timer := time.NewTimer(nextRotation(now, r.interval).Sub(now))
+		select {
+		case <-r.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if err := rotateIfNotEmpty(); err != nil {
+			_ = Errorf("time based rotation of the log file failed: %v", err)
+		}
+	}
+}
+
+// close stops the rotation. A rotation in progress may still complete
+func (r *timeRotation) close() {
+	close(r.stop)
+}
+
+// rotateIfNotEmpty rotates the log file, unless it is empty so that no zero
+// length backup is created
+func rotateIfNotEmpty() error {
+	loggingMutex.RLock()
+	filename := logger.Filename
+	loggingMutex.RUnlock()
+	if filename == "" {
+		return nil
+	}
+	info, err := os.Stat(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err != nil || info.Size() == 0 {
+		return nil
+	}
+	return Rotate()
+}
+
+// SetTimeRotation rotates the log file every interval, in addition to the
+// size based rotation of LogOptions. Rotations are aligned to the UTC wall
+// clock, so that a 24h interval starts a new log file at midnight UTC. An
+// empty log file is not rotated. Zero or less stops the time based rotation,
+// as does Close
+func SetTimeRotation(interval time.Duration) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	if loggingTimeRotation != nil {
+		loggingTimeRotation.close()
+		loggingTimeRotation = nil
+	}
+	if interval > 0 {
+		loggingTimeRotation = newTimeRotation(interval)
+	}
+}
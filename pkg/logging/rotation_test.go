@@ -0,0 +1,78 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	testutils "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging time based rotation", func() {
+	var tmpDir string
+
+	backups := func() []string {
+		matches, err := filepath.Glob(filepath.Join(tmpDir, "multus-*.log"))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		return matches
+	}
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		tmpDir = GinkgoT().TempDir()
+		gomega.Expect(SetLogFile(filepath.Join(tmpDir, "multus.log"))).To(gomega.Succeed())
+		gomega.Expect(SetLogOptions(&LogOptions{Compress: testutils.Bool(false)})).To(gomega.Succeed())
+		SetLogLevel("verbose")
+	})
+
+	AfterEach(func() {
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	It("Check rotations are aligned to the wall clock", func() {
+		t := time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+		gomega.Expect(nextRotation(t, 24*time.Hour)).To(gomega.Equal(time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC)))
+		gomega.Expect(nextRotation(t, time.Hour)).To(gomega.Equal(time.Date(2023, 1, 2, 16, 0, 0, 0, time.UTC)))
+	})
+
+	It("Check the log file is rotated on the interval", func() {
+		Verbosef("foobar")
+		SetTimeRotation(50 * time.Millisecond)
+		gomega.Eventually(backups).Should(gomega.HaveLen(1))
+		data, err := os.ReadFile(backups()[0])
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(string(data)).To(gomega.ContainSubstring("foobar"))
+
+		// the new log file is empty, so it is not rotated
+		gomega.Consistently(backups, 200*time.Millisecond).Should(gomega.HaveLen(1))
+	})
+
+	It("Check Close stops the time based rotation", func() {
+		SetTimeRotation(time.Hour)
+		gomega.Expect(loggingTimeRotation).NotTo(gomega.BeNil())
+		SetTimeRotation(0)
+		gomega.Expect(loggingTimeRotation).To(gomega.BeNil())
+
+		SetTimeRotation(time.Hour)
+		gomega.Expect(Close()).To(gomega.Succeed())
+		gomega.Expect(loggingTimeRotation).To(gomega.BeNil())
+	})
+})
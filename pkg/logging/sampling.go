@@ -0,0 +1,67 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"sync/atomic"
+)
+
+// sampler keeps one in n log lines of a level
+type sampler struct {
+	n       uint64
+	count   atomic.Uint64
+	dropped atomic.Uint64
+}
+
+// keep reports whether the log line is kept: the first of every n
+func (s *sampler) keep() bool {
+	if (s.count.Add(1)-1)%s.n == 0 {
+		return true
+	}
+	s.dropped.Add(1)
+	return false
+}
+
+// SetSampling keeps only one in n log lines of level, the first, the n+1th
+// and so on, and counts the others (see SampledOut). Sampling is applied
+// before the rate limit, which only sees the lines kept. One or less n
+// removes the sampling of level. Error, panic and fatal lines are never
+// sampled
+func SetSampling(level Level, n int) {
+	if level.severe() || !level.valid() {
+		return
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	if n <= 1 {
+		delete(loggingSamplers, level)
+		return
+	}
+	if loggingSamplers == nil {
+		loggingSamplers = make(map[Level]*sampler)
+	}
+	loggingSamplers[level] = &sampler{n: uint64(n)}
+}
+
+// SampledOut returns the number of log lines of level dropped by sampling
+// since SetSampling
+func SampledOut(level Level) uint64 {
+	loggingMutex.RLock()
+	defer loggingMutex.RUnlock()
+	if s, ok := loggingSamplers[level]; ok {
+		return s.dropped.Load()
+	}
+	return 0
+}
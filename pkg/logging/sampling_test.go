@@ -0,0 +1,70 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging sampling", func() {
+	var w *writeRecorder
+
+	BeforeEach(func() {
+		w = recordLogs(DebugLevel)
+	})
+
+	It("Check one in n lines of the level is kept", func() {
+		SetSampling(DebugLevel, 3)
+		for i := 1; i <= 7; i++ {
+			Debugf("debug %d", i)
+			Verbosef("verbose %d", i)
+		}
+		var debug []string
+		for _, l := range w.writes {
+			if l[:7] == "[debug]" {
+				debug = append(debug, l)
+			}
+		}
+		gomega.Expect(debug).To(gomega.Equal([]string{"[debug] debug 1\n", "[debug] debug 4\n", "[debug] debug 7\n"}))
+		gomega.Expect(w.writes).To(gomega.HaveLen(3 + 7))
+		gomega.Expect(SampledOut(DebugLevel)).To(gomega.Equal(uint64(4)))
+		gomega.Expect(SampledOut(VerboseLevel)).To(gomega.Equal(uint64(0)))
+
+		SetSampling(DebugLevel, 1)
+		Debugf("not sampled")
+		gomega.Expect(w.writes[len(w.writes)-1]).To(gomega.Equal("[debug] not sampled\n"))
+		gomega.Expect(SampledOut(DebugLevel)).To(gomega.Equal(uint64(0)))
+	})
+
+	It("Check errors are never sampled", func() {
+		SetSampling(ErrorLevel, 2)
+		SetSampling(PanicLevel, 2)
+		for i := 0; i < 3; i++ {
+			_ = Errorf("error %d", i)
+		}
+		gomega.Expect(w.writes).To(gomega.HaveLen(3))
+	})
+
+	It("Check the rate limit only sees the lines kept", func() {
+		SetSampling(DebugLevel, 2)
+		SetRateLimit(1, 2)
+		for i := 1; i <= 4; i++ {
+			Debugf("%d", i)
+		}
+		// lines 1 and 3 are kept by sampling, and fit in the burst
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[debug] 1\n", "[debug] 3\n"}))
+	})
+})
@@ -0,0 +1,85 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var signalMutex sync.Mutex
+var signalChan chan os.Signal
+var signalDone chan struct{}
+var signalWg sync.WaitGroup
+
+// EnableSignalLevelControl lets SIGUSR1 raise and SIGUSR2 lower the logging
+// level by one step, wrapping around within the valid levels
+func EnableSignalLevelControl() {
+	signalMutex.Lock()
+	defer signalMutex.Unlock()
+	if signalChan != nil {
+		return
+	}
+	signalChan = make(chan os.Signal, 1)
+	signalDone = make(chan struct{})
+	signal.Notify(signalChan, syscall.SIGUSR1, syscall.SIGUSR2)
+	signalWg.Add(1)
+	go handleLevelSignals(signalChan, signalDone)
+}
+
+// DisableSignalLevelControl stops the handling of SIGUSR1 and SIGUSR2
+func DisableSignalLevelControl() {
+	signalMutex.Lock()
+	defer signalMutex.Unlock()
+	if signalChan == nil {
+		return
+	}
+	signal.Stop(signalChan)
+	close(signalDone)
+	signalWg.Wait()
+	signalChan = nil
+	signalDone = nil
+}
+
+func handleLevelSignals(sigs <-chan os.Signal, done <-chan struct{}) {
+	defer signalWg.Done()
+	for {
+		select {
+		case <-done:
+			return
+		case sig := <-sigs:
+			stepLoggingLevel(sig == syscall.SIGUSR1)
+		}
+	}
+}
+
+// stepLoggingLevel raises or lowers the logging level by one, and logs the
+// change at the new level
+func stepLoggingLevel(up bool) Level {
+	loggingMutex.Lock()
+	level := loggingLevel.Load()
+	if up {
+		level = (level + 1) % MaxLevel
+	} else {
+		level = (level + MaxLevel - 1) % MaxLevel
+	}
+	setLevel(level)
+	loggingMutex.Unlock()
+
+	printf(nil, level, "log level changed to %s", level)
+	return level
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"syscall"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging level signal control", func() {
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+	})
+
+	AfterEach(func() {
+		DisableSignalLevelControl()
+	})
+
+	It("Check level steps wrap around", func() {
+		gomega.Expect(stepLoggingLevel(true)).To(gomega.Equal(ErrorLevel))
+		gomega.Expect(stepLoggingLevel(false)).To(gomega.Equal(PanicLevel))
+		gomega.Expect(stepLoggingLevel(false)).To(gomega.Equal(TraceLevel))
+		gomega.Expect(stepLoggingLevel(true)).To(gomega.Equal(PanicLevel))
+	})
+
+	It("Check SIGUSR1 and SIGUSR2 change the level", func() {
+		EnableSignalLevelControl()
+		// enabling twice is harmless
+		EnableSignalLevelControl()
+
+		gomega.Expect(syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)).To(gomega.Succeed())
+		gomega.Eventually(GetLoggingLevel).Should(gomega.Equal(ErrorLevel))
+		gomega.Expect(syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)).To(gomega.Succeed())
+		gomega.Eventually(GetLoggingLevel).Should(gomega.Equal(PanicLevel))
+
+		DisableSignalLevelControl()
+		gomega.Expect(signalChan).To(gomega.BeNil())
+	})
+})
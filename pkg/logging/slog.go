@@ -0,0 +1,106 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// slogHandler is a slog.Handler logging records with this package
+type slogHandler struct {
+	entry  *LogEntry
+	prefix string
+}
+
+// NewSlogHandler returns a slog.Handler logging records with this package,
+// so that they honor the logging level and destinations. Record levels map
+// to Error, Warning, Verbose (slog.LevelInfo) and Debug levels, and attributes
+// are logged as fields
+func NewSlogHandler() slog.Handler {
+	// slog.Logger methods call Handle through one unexported method, for the
+	// records without a program counter
+	return &slogHandler{entry: &LogEntry{callDepth: 2}}
+}
+
+// slogLevel converts a slog level into a Level
+func slogLevel(level slog.Level) Level {
+	switch {
+	case level >= slog.LevelError:
+		return ErrorLevel
+	case level >= slog.LevelWarn:
+		return WarningLevel
+	case level >= slog.LevelInfo:
+		return VerboseLevel
+	case level >= slog.LevelDebug:
+		return DebugLevel
+	}
+	return TraceLevel
+}
+
+// Enabled reports whether records of the level are logged
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return Enabled(slogLevel(level))
+}
+
+// Handle logs the record
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := map[string]interface{}{}
+	r.Attrs(func(a slog.Attr) bool {
+		addSlogAttr(fields, h.prefix, a)
+		return true
+	})
+	entry := h.entry.WithFields(fields).WithContext(ctx)
+	entry.pc = r.PC
+	printf(entry, slogLevel(r.Level), "%s", r.Message)
+	return nil
+}
+
+// WithAttrs returns a handler logging attrs with every record
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := map[string]interface{}{}
+	for _, a := range attrs {
+		addSlogAttr(fields, h.prefix, a)
+	}
+	return &slogHandler{entry: h.entry.WithFields(fields), prefix: h.prefix}
+}
+
+// WithGroup returns a handler qualifying the keys of attributes with name
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{entry: h.entry, prefix: h.prefix + name + "."}
+}
+
+// addSlogAttr adds the attribute to fields, flattening groups into dotted keys
+func addSlogAttr(fields map[string]interface{}, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			addSlogAttr(fields, prefix, ga)
+		}
+		return
+	}
+	fields[prefix+a.Key] = a.Value.Any()
+}
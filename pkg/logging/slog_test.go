@@ -0,0 +1,80 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging slog handler", func() {
+
+	var w *writeRecorder
+
+	BeforeEach(func() {
+		w = recordLogs(VerboseLevel)
+	})
+
+	It("Check slog levels are mapped and filtered", func() {
+		logger := slog.New(NewSlogHandler())
+		logger.Debug("foobar")
+		logger.Info("foobar")
+		logger.Warn("foobar")
+		logger.Error("foobar")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			"[verbose] foobar\n",
+			"[warning] foobar\n",
+			"[error] foobar\n",
+		}))
+		gomega.Expect(logger.Enabled(context.Background(), slog.LevelDebug)).To(gomega.BeFalse())
+		gomega.Expect(logger.Enabled(context.Background(), slog.LevelInfo)).To(gomega.BeTrue())
+	})
+
+	It("Check slog attributes are logged as fields", func() {
+		logger := slog.New(NewSlogHandler()).With("pod", "default/foo").WithGroup("net")
+		logger.Info("attaching", "ifname", "net1", slog.Group("ip", "v4", "10.1.1.2"))
+		gomega.Expect(w.writes[0]).To(gomega.Equal("[verbose] attaching net.ifname=net1 net.ip.v4=10.1.1.2 pod=default/foo\n"))
+
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		logger.InfoContext(ContextWithID(context.Background(), "7f3a"), "attaching", "mtu", 1500)
+		gomega.Expect(w.writes[1]).To(gomega.Equal(`{"level":"verbose","msg":"attaching","id":"7f3a","net.mtu":1500,"pod":"default/foo","schemaVersion":"1"}` + "\n"))
+	})
+
+	It("Check slog caller is the logging code", func() {
+		SetLogCaller(true)
+		slog.New(NewSlogHandler()).Info("foobar")
+		gomega.Expect(w.writes[0]).To(gomega.MatchRegexp(`^\[verbose\] slog_test\.go:\d+ foobar\n$`))
+	})
+
+	It("Check slog caller is the program counter of the record", func() {
+		SetLogCaller(true)
+		var pcs [1]uintptr
+		runtime.Callers(1, pcs[:])
+		_, _, line, _ := runtime.Caller(0)
+		logger := slog.New(NewSlogHandler())
+		logger.LogAttrs(context.Background(), slog.LevelInfo, "foobar")
+		gomega.Expect(logger.Handler().Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "recorded", pcs[0]))).To(gomega.Succeed())
+		gomega.Expect(w.writes[0]).To(gomega.MatchRegexp(`^\[verbose\] slog_test\.go:%d foobar\n$`, line+2))
+		gomega.Expect(w.writes[1]).To(gomega.Equal(fmt.Sprintf("[verbose] slog_test.go:%d recorded\n", line-1)))
+	})
+})
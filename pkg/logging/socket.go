@@ -0,0 +1,238 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// defaultSocketBufferSize is the number of log lines kept for the socket
+// while it is disconnected
+const defaultSocketBufferSize = 1024
+
+// minSocketBackoff and maxSocketBackoff bound the delay between the attempts
+// to reconnect to the socket
+const (
+	minSocketBackoff = 100 * time.Millisecond
+	maxSocketBackoff = 30 * time.Second
+)
+
+// socketWriteTimeout bounds a write to the socket, so that an agent which
+// stops reading does not block the logging calls. Tests shorten it
+var socketWriteTimeout = time.Second
+
+// socketWriter writes each log line as one message to a unix socket,
+// reconnecting with backoff when the write fails, e.g. when the agent
+// listening on the socket restarts
+type socketWriter struct {
+	mu      sync.Mutex
+	path    string
+	network string
+	conn    net.Conn
+	// bufferSize is the maximum number of lines in pending, zero drops the
+	// lines while disconnected
+	bufferSize int
+	pending    [][]byte
+	backoff    time.Duration
+	retryAt    time.Time
+}
+
+// dialSocket connects to the unixgram or unix socket at path
+func dialSocket(path string) (net.Conn, string, error) {
+	var err error
+	for _, network := range []string{"unixgram", "unix"} {
+		var conn net.Conn
+		if conn, err = net.Dial(network, path); err == nil {
+			return conn, network, nil
+		}
+	}
+	return nil, "", fmt.Errorf("failed to connect to log socket %s: %w", path, err)
+}
+
+func (s *socketWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil && !s.reconnect() {
+		return s.keep(p)
+	}
+	for len(s.pending) > 0 {
+		if err := s.write(s.pending[0]); err != nil && !unsendable(err) {
+			s.disconnect()
+			return s.keep(p)
+		}
+		// the line is sent, or dropped when it can never be
+		s.pending = s.pending[1:]
+	}
+	if err := s.write(p); err != nil {
+		if unsendable(err) {
+			return 0, fmt.Errorf("log line dropped by log socket %s: %w", s.path, err)
+		}
+		s.disconnect()
+		return s.keep(p)
+	}
+	s.backoff = 0
+	return len(p), nil
+}
+
+// unsendable reports whether the write failed because of the line itself,
+// e.g. a line larger than a datagram may be, so that writing it again would
+// fail as well
+func unsendable(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE)
+}
+
+// write writes p to the connection within socketWriteTimeout. The caller
+// must hold s.mu
+func (s *socketWriter) write(p []byte) error {
+	if err := s.conn.SetWriteDeadline(time.Now().Add(socketWriteTimeout)); err != nil {
+		return err
+	}
+	_, err := s.conn.Write(p)
+	return err
+}
+
+// reconnect connects to the socket again, unless it failed less than a
+// backoff ago, and reports whether it did. The caller must hold s.mu
+func (s *socketWriter) reconnect() bool {
+	if time.Now().Before(s.retryAt) {
+		return false
+	}
+	conn, err := net.Dial(s.network, s.path)
+	if err != nil {
+		s.retryLater()
+		return false
+	}
+	s.conn = conn
+	return true
+}
+
+// retryLater doubles the backoff before the next attempt to connect. The
+// caller must hold s.mu
+func (s *socketWriter) retryLater() {
+	s.backoff *= 2
+	if s.backoff < minSocketBackoff {
+		s.backoff = minSocketBackoff
+	} else if s.backoff > maxSocketBackoff {
+		s.backoff = maxSocketBackoff
+	}
+	s.retryAt = time.Now().Add(s.backoff)
+}
+
+// disconnect closes the connection after a failed or timed out write, e.g.
+// when the agent stops reading, and the next write reconnects after the
+// backoff. The backoff is reset by a successful write. The caller must hold
+// s.mu
+func (s *socketWriter) disconnect() {
+	s.conn.Close()
+	s.conn = nil
+	s.retryLater()
+}
+
+// keep buffers a copy of the line until the socket is connected again,
+// dropping the oldest line when the buffer is full. The caller must hold s.mu
+func (s *socketWriter) keep(p []byte) (int, error) {
+	if s.bufferSize <= 0 {
+		return 0, errors.New("log socket is disconnected")
+	}
+	if len(s.pending) >= s.bufferSize {
+		s.pending = s.pending[len(s.pending)-s.bufferSize+1:]
+	}
+	s.pending = append(s.pending, append([]byte(nil), p...))
+	return len(p), nil
+}
+
+// setBufferSize sets the maximum number of lines buffered while disconnected
+func (s *socketWriter) setBufferSize(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bufferSize = size
+	if size <= 0 {
+		s.pending = nil
+	} else if len(s.pending) > size {
+		s.pending = s.pending[len(s.pending)-size:]
+	}
+}
+
+// Close closes the connection and drops the buffered lines
+func (s *socketWriter) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = nil
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// SetSocketOutput sends log lines to the unixgram or unix socket at path, e.g.
+// of a log forwarding agent, one message per line. The socket is an
+// additional destination, and replaces the previous one. When writing fails,
+// e.g. when the agent restarts, it reconnects with backoff, from 100ms up to
+// 30s, keeping the lines meanwhile as set by SetSocketBuffer. Lines which can
+// never be sent, such as the lines larger than a datagram, are dropped
+func SetSocketOutput(path string) error {
+	conn, network, err := dialSocket(path)
+	if err != nil {
+		return err
+	}
+
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	closeSocket()
+	loggingSocket = &socketWriter{
+		path:       path,
+		network:    network,
+		conn:       conn,
+		bufferSize: loggingSocketBufferSize,
+	}
+	return nil
+}
+
+// SetSocketBuffer sets the number of log lines kept while the socket of
+// SetSocketOutput is disconnected, 1024 by default. The oldest lines are
+// dropped when it is full. Zero or less drops all of the lines meanwhile
+func SetSocketBuffer(lines int) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingSocketBufferSize = lines
+	if loggingSocket != nil {
+		loggingSocket.setBufferSize(lines)
+	}
+}
+
+// DisableSocketOutput stops sending log lines to the socket
+func DisableSocketOutput() error {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	return closeSocket()
+}
+
+// closeSocket closes the socket, if any, and stops sending log lines to it.
+// The caller must hold loggingMutex
+func closeSocket() error {
+	if loggingSocket == nil {
+		return nil
+	}
+	err := loggingSocket.Close()
+	loggingSocket = nil
+	return err
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging to a unix socket", func() {
+	var path string
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		loggingLevel.Store(DebugLevel)
+		SetTimestamp(false)
+		path = filepath.Join(GinkgoT().TempDir(), "agent.sock")
+	})
+
+	AfterEach(func() {
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	// listen listens on a unixgram socket at path
+	listen := func() *net.UnixConn {
+		conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		return conn
+	}
+
+	// receive returns the next message received on conn
+	receive := func(conn *net.UnixConn) string {
+		buf := make([]byte, 1024)
+		gomega.Expect(conn.SetReadDeadline(time.Now().Add(5 * time.Second))).To(gomega.Succeed())
+		n, err := conn.Read(buf)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		return string(buf[:n])
+	}
+
+	It("Check each log line is a message on a unixgram socket", func() {
+		agent := listen()
+		defer agent.Close()
+		gomega.Expect(SetSocketOutput(path)).To(gomega.Succeed())
+		Verbosef("first")
+		Debugf("second")
+		gomega.Expect(receive(agent)).To(gomega.Equal("[verbose] first\n"))
+		gomega.Expect(receive(agent)).To(gomega.Equal("[debug] second\n"))
+	})
+
+	It("Check log lines are written to a unix stream socket", func() {
+		l, err := net.Listen("unix", path)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		defer l.Close()
+		gomega.Expect(SetSocketOutput(path)).To(gomega.Succeed())
+		conn, err := l.Accept()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		defer conn.Close()
+		Verbosef("foobar")
+		buf := make([]byte, 1024)
+		n, err := conn.Read(buf)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(string(buf[:n])).To(gomega.Equal("[verbose] foobar\n"))
+	})
+
+	It("Check a write to an agent which stops reading times out", func() {
+		defer func(timeout time.Duration) { socketWriteTimeout = timeout }(socketWriteTimeout)
+		socketWriteTimeout = 50 * time.Millisecond
+		l, err := net.Listen("unix", path)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		defer l.Close()
+		gomega.Expect(SetSocketOutput(path)).To(gomega.Succeed())
+		conn, err := l.Accept()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		defer conn.Close()
+
+		line := strings.Repeat("x", 4096)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 1000; i++ {
+				Verbosef("%s", line)
+			}
+		}()
+		gomega.Eventually(done, 5*time.Second).Should(gomega.BeClosed())
+		gomega.Expect(loggingSocket.conn).To(gomega.BeNil())
+		gomega.Expect(loggingSocket.retryAt).To(gomega.BeTemporally(">", time.Now()))
+		gomega.Expect(loggingSocket.pending).NotTo(gomega.BeEmpty())
+	})
+
+	It("Check the lines are kept and sent again when the agent restarts", func() {
+		agent := listen()
+		gomega.Expect(SetSocketOutput(path)).To(gomega.Succeed())
+		Verbosef("first")
+		gomega.Expect(receive(agent)).To(gomega.Equal("[verbose] first\n"))
+
+		gomega.Expect(agent.Close()).To(gomega.Succeed())
+		gomega.Expect(os.Remove(path)).To(gomega.Succeed())
+		Verbosef("kept")
+		Verbosef("backing off")
+		gomega.Expect(loggingSocket.conn).To(gomega.BeNil())
+		gomega.Expect(loggingSocket.retryAt).To(gomega.BeTemporally(">", time.Now()))
+
+		agent = listen()
+		defer agent.Close()
+		loggingSocket.retryAt = time.Time{}
+		Verbosef("reconnected")
+		gomega.Expect(receive(agent)).To(gomega.Equal("[verbose] kept\n"))
+		gomega.Expect(receive(agent)).To(gomega.Equal("[verbose] backing off\n"))
+		gomega.Expect(receive(agent)).To(gomega.Equal("[verbose] reconnected\n"))
+	})
+
+	It("Check a line too large for a datagram does not block the next lines", func() {
+		agent := listen()
+		gomega.Expect(SetSocketOutput(path)).To(gomega.Succeed())
+		oversized := strings.Repeat("x", 1<<20)
+		Verbosef("%s", oversized)
+		Verbosef("first")
+		gomega.Expect(receive(agent)).To(gomega.Equal("[verbose] first\n"))
+
+		// a kept line which cannot be sent is dropped rather than retried
+		gomega.Expect(agent.Close()).To(gomega.Succeed())
+		gomega.Expect(os.Remove(path)).To(gomega.Succeed())
+		loggingSocket.pending = [][]byte{[]byte(oversized)}
+		Verbosef("kept")
+		gomega.Expect(loggingSocket.pending).To(gomega.Equal([][]byte{[]byte("[verbose] kept\n")}))
+
+		agent = listen()
+		defer agent.Close()
+		loggingSocket.retryAt = time.Time{}
+		Verbosef("reconnected")
+		gomega.Expect(receive(agent)).To(gomega.Equal("[verbose] kept\n"))
+		gomega.Expect(receive(agent)).To(gomega.Equal("[verbose] reconnected\n"))
+		gomega.Expect(loggingSocket.pending).To(gomega.BeEmpty())
+	})
+
+	It("Check the lines are dropped while disconnected without buffer", func() {
+		agent := listen()
+		gomega.Expect(SetSocketOutput(path)).To(gomega.Succeed())
+		SetSocketBuffer(0)
+		gomega.Expect(agent.Close()).To(gomega.Succeed())
+		gomega.Expect(os.Remove(path)).To(gomega.Succeed())
+		Verbosef("dropped")
+
+		agent = listen()
+		defer agent.Close()
+		loggingSocket.retryAt = time.Time{}
+		Verbosef("sent")
+		gomega.Expect(receive(agent)).To(gomega.Equal("[verbose] sent\n"))
+	})
+
+	It("Check an unreachable socket is an error", func() {
+		gomega.Expect(SetSocketOutput(path)).To(gomega.MatchError(gomega.ContainSubstring("failed to connect to log socket " + path)))
+		gomega.Expect(loggingSocket).To(gomega.BeNil())
+		gomega.Expect(DisableSocketOutput()).To(gomega.Succeed())
+	})
+})
@@ -0,0 +1,31 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+// LogStartupInfo logs at verbose level a single line describing the build
+// and the logging configuration, so that a log file tells what produced it.
+// It is meant to be called once, after the logging configuration is loaded
+func LogStartupInfo(version string) {
+	options := GetLogOptions()
+	WithFields(map[string]interface{}{
+		"version":    version,
+		"logLevel":   GetLoggingLevel().String(),
+		"logFile":    GetLogFile(),
+		"maxSize":    *options.MaxSize,
+		"maxAge":     *options.MaxAge,
+		"maxBackups": *options.MaxBackups,
+		"compress":   *options.Compress,
+	}).Verbosef("logging started")
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"path/filepath"
+
+	testutils "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging startup info", func() {
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+	})
+
+	AfterEach(func() {
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	It("Check the startup line describes the configuration", func() {
+		w := &writeRecorder{}
+		AddOutput(w)
+		SetTimestamp(false)
+		logFile := filepath.Join(GinkgoT().TempDir(), "multus.log")
+		gomega.Expect(SetLogFile(logFile)).To(gomega.Succeed())
+		gomega.Expect(SetLogOptions(&LogOptions{MaxSize: testutils.Int(10), Compress: testutils.Bool(false)})).To(gomega.Succeed())
+		SetLogLevel("verbose")
+
+		LogStartupInfo("v4.0.0")
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			"[verbose] logging started compress=false logFile=" + logFile +
+				" logLevel=verbose maxAge=5 maxBackups=5 maxSize=10 version=v4.0.0\n",
+		}))
+
+		SetLogLevel("error")
+		LogStartupInfo("v4.0.0")
+		gomega.Expect(w.writes).To(gomega.HaveLen(1))
+	})
+})
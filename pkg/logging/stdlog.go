@@ -0,0 +1,58 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"io"
+	"log"
+	"sync"
+)
+
+// standardLog is the configuration of the standard library default logger
+// replaced by RedirectStandardLog
+type standardLog struct {
+	w     io.Writer
+	flags int
+}
+
+var standardLogMutex sync.Mutex
+var savedStandardLog *standardLog
+
+// RedirectStandardLog logs the lines written by the standard library log
+// package default logger, e.g. by dependencies calling log.Printf, at level
+// through this package, as LevelWriter does. The log flags are cleared so
+// that the lines do not get a second timestamp. RestoreStandardLog undoes it
+func RedirectStandardLog(level Level) {
+	standardLogMutex.Lock()
+	defer standardLogMutex.Unlock()
+	if savedStandardLog == nil {
+		savedStandardLog = &standardLog{w: log.Writer(), flags: log.Flags()}
+	}
+	log.SetOutput(LevelWriter(level))
+	log.SetFlags(0)
+}
+
+// RestoreStandardLog restores the output and the flags of the standard
+// library log package default logger replaced by RedirectStandardLog
+func RestoreStandardLog() {
+	standardLogMutex.Lock()
+	defer standardLogMutex.Unlock()
+	if savedStandardLog == nil {
+		return
+	}
+	log.SetOutput(savedStandardLog.w)
+	log.SetFlags(savedStandardLog.flags)
+	savedStandardLog = nil
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"log"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging standard library log redirection", func() {
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		SetTimestamp(false)
+		SetLogLevel("verbose")
+	})
+
+	AfterEach(func() {
+		RestoreStandardLog()
+	})
+
+	It("Check the standard log lines are logged at the level", func() {
+		c := CaptureLogs()
+		defer c.Close()
+		flags := log.Flags()
+		RedirectStandardLog(WarningLevel)
+		RedirectStandardLog(WarningLevel)
+		log.Printf("dependency %s", "warning")
+		log.Print("no newline")
+		gomega.Expect(c.Lines()).To(gomega.Equal([]string{"[warning] dependency warning", "[warning] no newline"}))
+		gomega.Expect(log.Flags()).To(gomega.Equal(0))
+
+		RestoreStandardLog()
+		gomega.Expect(log.Writer()).To(gomega.Equal(os.Stderr))
+		gomega.Expect(log.Flags()).To(gomega.Equal(flags))
+		RestoreStandardLog()
+	})
+})
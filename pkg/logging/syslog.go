@@ -0,0 +1,166 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"log/syslog"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultSyslogApp is the APP-NAME of the syslog format log lines
+const defaultSyslogApp = "multus"
+
+// syslogTimestampFormat is the RFC 5424 timestamp, which has at most
+// microseconds
+const syslogTimestampFormat = "2006-01-02T15:04:05.999999Z07:00"
+
+// syslogHostname and syslogProcID are the HOSTNAME and PROCID of the syslog
+// format log lines
+var syslogHostname = hostname()
+var syslogProcID = strconv.Itoa(os.Getpid())
+
+// hostname returns the host name, or the RFC 5424 nil value when unknown
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		return "-"
+	}
+	return name
+}
+
+// SetSyslog sends log lines to the syslog daemon at addr, or to the local
+// syslog daemon when network is empty, with the given tag. Syslog is an
+// additional destination: to log to syslog only, leave the log file unset and
+// disable stderr. Log levels map to syslog severities, e.g. error to LOG_ERR,
+// verbose to LOG_INFO, debug to LOG_DEBUG and panic to LOG_CRIT
+func SetSyslog(network, addr, tag string) error {
+	w, err := syslog.Dial(network, addr, syslog.LOG_DAEMON|syslog.LOG_INFO, tag)
+	if err != nil {
+		return err
+	}
+
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	if loggingSyslog != nil {
+		loggingSyslog.Close()
+	}
+	loggingSyslog = w
+	return nil
+}
+
+// DisableSyslog stops sending log lines to syslog
+func DisableSyslog() error {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	if loggingSyslog == nil {
+		return nil
+	}
+	err := loggingSyslog.Close()
+	loggingSyslog = nil
+	return err
+}
+
+// SetSyslogLineOptions sets the APP-NAME, "multus" when empty, and the
+// facility, such as syslog.LOG_DAEMON or syslog.LOG_LOCAL0, of the log lines
+// in the syslog format. It does not change the lines sent by SetSyslog
+func SetSyslogLineOptions(appName string, facility syslog.Priority) error {
+	if appName == "" {
+		appName = defaultSyslogApp
+	}
+	if len(appName) > 48 || strings.IndexFunc(appName, func(r rune) bool { return r <= ' ' || r > '~' }) >= 0 {
+		return fmt.Errorf("invalid syslog app name %q, must be at most 48 printable ASCII characters", appName)
+	}
+	if facility&7 != 0 || facility > syslog.LOG_LOCAL7 {
+		return fmt.Errorf("invalid syslog facility %d", facility)
+	}
+
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingSyslogApp = appName
+	loggingSyslogFacility = facility
+	return nil
+}
+
+// formatSyslog renders a whole log line into buf in the RFC 5424 format,
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID - MSG, the component being
+// the MSGID. The message is followed by the fields as in text log lines
+func formatSyslog(buf *bytes.Buffer, r *record) {
+	buf.WriteByte('<')
+	buf.WriteString(strconv.Itoa(int(loggingSyslogFacility | syslogSeverity(r.level))))
+	buf.WriteString(">1 ")
+	if loggingTimestamp {
+		var ts [64]byte
+		buf.Write(r.time.AppendFormat(ts[:0], syslogTimestampFormat))
+	} else {
+		buf.WriteByte('-')
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(syslogHostname)
+	buf.WriteByte(' ')
+	buf.WriteString(loggingSyslogApp)
+	buf.WriteByte(' ')
+	buf.WriteString(syslogProcID)
+	buf.WriteByte(' ')
+	if r.component != "" {
+		buf.WriteString(r.component)
+	} else {
+		buf.WriteByte('-')
+	}
+	buf.WriteString(" - ")
+	if r.caller != "" {
+		buf.WriteString(r.caller)
+		buf.WriteByte(' ')
+	}
+	appendMessage(buf, r.msg)
+	appendTextFields(buf, r)
+	buf.WriteByte('\n')
+}
+
+// syslogSeverity returns the syslog severity of the level, as writeSyslog
+// sends it
+func syslogSeverity(level Level) syslog.Priority {
+	switch level {
+	case PanicLevel, FatalLevel:
+		return syslog.LOG_CRIT
+	case ErrorLevel:
+		return syslog.LOG_ERR
+	case WarningLevel:
+		return syslog.LOG_WARNING
+	case VerboseLevel:
+		return syslog.LOG_INFO
+	}
+	return syslog.LOG_DEBUG
+}
+
+// writeSyslog sends the line to syslog w with the severity of the level
+func writeSyslog(w *syslog.Writer, level Level, line []byte) {
+	m := string(bytes.TrimSuffix(line, []byte("\n")))
+	switch level {
+	case PanicLevel, FatalLevel:
+		w.Crit(m)
+	case ErrorLevel:
+		w.Err(m)
+	case WarningLevel:
+		w.Warning(m)
+	case VerboseLevel:
+		w.Info(m)
+	default:
+		w.Debug(m)
+	}
+}
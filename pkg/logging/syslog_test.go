@@ -0,0 +1,112 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"log/syslog"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging to syslog", func() {
+
+	var tmpDir string
+	var conn net.PacketConn
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		loggingLevel.Store(DebugLevel)
+		SetTimestamp(false)
+
+		var err error
+		tmpDir, err = os.MkdirTemp("", "multus_tmp")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		conn, err = net.ListenPacket("unixgram", filepath.Join(tmpDir, "log.sock"))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	AfterEach(func() {
+		gomega.Expect(DisableSyslog()).To(gomega.Succeed())
+		conn.Close()
+		gomega.Expect(os.RemoveAll(tmpDir)).To(gomega.Succeed())
+	})
+
+	receive := func() string {
+		buf := make([]byte, 1024)
+		gomega.Expect(conn.SetReadDeadline(time.Now().Add(5 * time.Second))).To(gomega.Succeed())
+		n, _, err := conn.ReadFrom(buf)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		return string(buf[:n])
+	}
+
+	It("Check log lines are sent with the level severity", func() {
+		gomega.Expect(SetSyslog("unixgram", filepath.Join(tmpDir, "log.sock"), "multus")).To(gomega.Succeed())
+		gomega.Expect(Errorf("foobar")).To(gomega.HaveOccurred())
+		// LOG_DAEMON|LOG_ERR
+		gomega.Expect(receive()).To(gomega.MatchRegexp(`^<27>.* multus\[\d+\]: \[error\] foobar\n$`))
+		Debugf("foobar")
+		// LOG_DAEMON|LOG_DEBUG
+		gomega.Expect(receive()).To(gomega.MatchRegexp(`^<31>.*\[debug\] foobar\n$`))
+		Panicf("foobar")
+		// LOG_DAEMON|LOG_CRIT
+		gomega.Expect(receive()).To(gomega.MatchRegexp(`^<26>.*\[panic\] foobar\n$`))
+	})
+
+	It("Check unreachable syslog returns an error", func() {
+		gomega.Expect(SetSyslog("unixgram", filepath.Join(tmpDir, "missing.sock"), "multus")).NotTo(gomega.Succeed())
+		gomega.Expect(loggingSyslog).To(gomega.BeNil())
+	})
+})
+
+var _ = Describe("logging in the syslog format", func() {
+
+	var w *writeRecorder
+
+	BeforeEach(func() {
+		w = recordLogs(DebugLevel)
+		SetTimestamp(true)
+		gomega.Expect(SetLogFormat("syslog")).To(gomega.Succeed())
+		SetClock(func() time.Time { return time.Date(2023, 5, 1, 10, 20, 30, 123456789, time.UTC) })
+	})
+
+	It("Check the log lines are in the RFC 5424 format", func() {
+		_ = Errorf("foobar")
+		Named("cache").WithField("ifname", "net1").Debugf("foo bar")
+		prefix := "1 2023-05-01T10:20:30.123456Z " + syslogHostname + " multus " + syslogProcID + " "
+		gomega.Expect(w.writes).To(gomega.Equal([]string{
+			"<27>" + prefix + "- - foobar\n",
+			"<31>" + prefix + "cache - foo bar ifname=net1\n",
+		}))
+	})
+
+	It("Check the app name and the facility are set", func() {
+		gomega.Expect(SetSyslogLineOptions("cni", syslog.LOG_LOCAL0)).To(gomega.Succeed())
+		SetTimestamp(false)
+		Warningf("foobar")
+		gomega.Expect(w.writes[0]).To(gomega.Equal("<132>1 - " + syslogHostname + " cni " + syslogProcID + " - - foobar\n"))
+
+		gomega.Expect(SetSyslogLineOptions("multus cni", syslog.LOG_LOCAL0)).To(gomega.MatchError(`invalid syslog app name "multus cni", must be at most 48 printable ASCII characters`))
+		gomega.Expect(SetSyslogLineOptions("", syslog.LOG_ERR)).To(gomega.MatchError("invalid syslog facility 3"))
+		gomega.Expect(loggingSyslogApp).To(gomega.Equal("cni"))
+		gomega.Expect(SetSyslogLineOptions("", syslog.LOG_DAEMON)).To(gomega.Succeed())
+		gomega.Expect(loggingSyslogApp).To(gomega.Equal("multus"))
+	})
+})
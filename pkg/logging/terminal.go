@@ -0,0 +1,39 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"os"
+
+	"golang.org/x/term"
+)
+
+// stderrTerminal caches whether stderr is a terminal
+var stderrTerminal bool
+
+// detectStderrTerminal updates stderrTerminal. The caller must hold
+// loggingMutex
+func detectStderrTerminal() {
+	stderrTerminal = term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// StderrIsTerminal reports whether stderr is a terminal, rather than e.g. a
+// pipe or a file, so that control sequences can be written to it. It is
+// detected once, and again whenever SetLogStderr is called
+func StderrIsTerminal() bool {
+	loggingMutex.RLock()
+	defer loggingMutex.RUnlock()
+	return stderrTerminal
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+// durationField is the field of json log lines with the duration of Timer
+const durationField = "duration_ms"
+
+// Timer returns a function printing "name took <duration>" since the call of
+// Timer, if logging level >= debug, meant to time a function with
+//
+//	defer logging.Timer("cni-add")()
+//
+// Json log lines also get the duration in milliseconds as the duration_ms
+// field
+func Timer(name string) func() {
+	start := nowFunc()
+	return func() {
+		d := nowFunc().Sub(start)
+		loggingMutex.RLock()
+		structured := loggingFormat == jsonFormat
+		loggingMutex.RUnlock()
+		var e *LogEntry
+		if structured {
+			e = WithField(durationField, float64(d)/1e6)
+		}
+		printf(e, DebugLevel, "%s took %s", name, d)
+	}
+}
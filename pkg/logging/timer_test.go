@@ -0,0 +1,56 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging timer", func() {
+	var w *writeRecorder
+	var now time.Time
+
+	BeforeEach(func() {
+		w = recordLogs(DebugLevel)
+		now = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+		SetClock(func() time.Time { return now })
+	})
+
+	It("Check the timer logs the duration", func() {
+		func() {
+			defer Timer("cni-add")()
+			now = now.Add(1500 * time.Millisecond)
+		}()
+		gomega.Expect(w.writes).To(gomega.Equal([]string{"[debug] cni-add took 1.5s\n"}))
+	})
+
+	It("Check the timer adds the duration_ms field to json log lines", func() {
+		gomega.Expect(SetLogFormat("json")).To(gomega.Succeed())
+		SetLogCaller(true)
+		stop := Timer("cni-del")
+		now = now.Add(2250 * time.Microsecond)
+		stop()
+		gomega.Expect(w.writes[0]).To(gomega.MatchRegexp(`^{"level":"debug","caller":"timer_test\.go:\d+","msg":"cni-del took 2\.25ms","duration_ms":2\.25,`))
+	})
+
+	It("Check the timer logs nothing above the logging level", func() {
+		loggingLevel.Store(VerboseLevel)
+		Timer("cni-check")()
+		gomega.Expect(w.writes).To(gomega.BeEmpty())
+	})
+})
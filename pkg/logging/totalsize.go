@@ -0,0 +1,251 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	lumberjack "gopkg.in/natefinch/lumberjack.v2"
+)
+
+const megabyte = 1024 * 1024
+
+// lumberjackDefaultMaxSize is the size in megabytes of the log file before
+// it gets rotated when MaxSize is unset
+const lumberjackDefaultMaxSize = 100
+
+// lumberjackBackupTimeFormat is the timestamp lumberjack puts in the name of
+// the backups
+const lumberjackBackupTimeFormat = "2006-01-02T15-04-05.000"
+
+// totalSizeCap removes the oldest backups of the log file after a rotation,
+// until the log file and its backups fit in maxTotal bytes. lumberjack has no
+// rotation hook, so the rotations are told by tracking the size of the log
+// file as lumberjack does. With zstd set, it also compresses the backups with
+// zstd in the background, and removes them by MaxBackups and MaxAge, which
+// lumberjack does not recognize
+type totalSizeCap struct {
+	mu       sync.Mutex
+	filename string
+	maxSize  int64
+	maxTotal int64
+	size     int64
+	sized    bool
+	// maxTotalSize is maxTotal in megabytes, zero does not cap the total size
+	maxTotalSize int
+
+	zstd       bool
+	maxAge     int
+	maxBackups int
+	localTime  bool
+	// millMu serializes the background compressions
+	millMu sync.Mutex
+}
+
+func newTotalSizeCap(l *lumberjack.Logger, maxTotalSize int, zstd bool) *totalSizeCap {
+	maxSize := l.MaxSize
+	if maxSize == 0 {
+		maxSize = lumberjackDefaultMaxSize
+	}
+	return &totalSizeCap{
+		filename:     l.Filename,
+		maxSize:      int64(maxSize) * megabyte,
+		maxTotal:     int64(maxTotalSize) * megabyte,
+		maxTotalSize: maxTotalSize,
+		zstd:         zstd,
+		maxAge:       l.MaxAge,
+		maxBackups:   l.MaxBackups,
+		localTime:    l.LocalTime,
+	}
+}
+
+// wrote accounts n bytes written to the log file, and sweeps the backups
+// when the write rotated the log file
+func (c *totalSizeCap) wrote(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.sized {
+		if info, err := os.Stat(c.filename); err == nil {
+			c.size = info.Size()
+		}
+		c.sized = true
+		return
+	}
+	if c.size+int64(n) > c.maxSize {
+		c.size = int64(n)
+		c.afterRotation()
+		return
+	}
+	c.size += int64(n)
+}
+
+// rotated sweeps the backups after a rotation of the log file
+func (c *totalSizeCap) rotated() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.size = 0
+	c.sized = true
+	c.afterRotation()
+}
+
+// afterRotation handles the new backup. The caller must hold c.mu
+func (c *totalSizeCap) afterRotation() {
+	if c.zstd {
+		go c.mill()
+		return
+	}
+	c.sweep()
+}
+
+// mill compresses the backups with zstd, then removes the backups out of
+// MaxBackups, MaxAge and the total size
+func (c *totalSizeCap) mill() {
+	c.millMu.Lock()
+	defer c.millMu.Unlock()
+	backups := c.backups()
+	for i, b := range backups {
+		if strings.HasSuffix(b.name, zstdSuffix) || strings.HasSuffix(b.name, ".gz") {
+			continue
+		}
+		if err := compressZstd(b.path()); err == nil {
+			backups[i].name += zstdSuffix
+		}
+	}
+	c.removeOld(backups)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sweep()
+}
+
+// backup is a backup of the log file, with the time of its rotation
+type backup struct {
+	dir  string
+	name string
+	time time.Time
+}
+
+func (b backup) path() string {
+	return filepath.Join(b.dir, b.name)
+}
+
+// backups returns the backups of the log file, newest first
+func (c *totalSizeCap) backups() []backup {
+	backups, _ := logBackups(c.filename, c.localTime)
+	return backups
+}
+
+// logBackups returns the backups lumberjack made of the log file filename,
+// compressed or not, newest first. localTime tells the time zone of their
+// names
+func logBackups(filename string, localTime bool) ([]backup, error) {
+	dir := filepath.Dir(filename)
+	name := filepath.Base(filename)
+	ext := filepath.Ext(name)
+	prefix := strings.TrimSuffix(name, ext) + "-"
+	loc := time.UTC
+	if localTime {
+		loc = time.Local
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var backups []backup
+	for _, e := range entries {
+		n := e.Name()
+		if e.IsDir() || !strings.HasPrefix(n, prefix) {
+			continue
+		}
+		ts := strings.TrimPrefix(n, prefix)
+		for _, suffix := range []string{".gz", zstdSuffix} {
+			ts = strings.TrimSuffix(ts, suffix)
+		}
+		if !strings.HasSuffix(ts, ext) {
+			continue
+		}
+		t, err := time.ParseInLocation(lumberjackBackupTimeFormat, strings.TrimSuffix(ts, ext), loc)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{dir: dir, name: n, time: t})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.After(backups[j].time)
+	})
+	return backups, nil
+}
+
+// removeOld removes the backups, newest first, out of MaxBackups and MaxAge
+func (c *totalSizeCap) removeOld(backups []backup) {
+	cutoff := time.Now().Add(-time.Duration(c.maxAge) * 24 * time.Hour)
+	for i, b := range backups {
+		if (c.maxBackups > 0 && i >= c.maxBackups) || (c.maxAge > 0 && b.time.Before(cutoff)) {
+			os.Remove(b.path())
+		}
+	}
+}
+
+// sweep removes the oldest backups until the log file and its backups fit in
+// maxTotal bytes. Backups are named by lumberjack after the log file with a
+// timestamp, which sorts them by age
+func (c *totalSizeCap) sweep() {
+	if c.filename == "" || c.maxTotal == 0 {
+		return
+	}
+	dir := filepath.Dir(c.filename)
+	name := filepath.Base(c.filename)
+	ext := filepath.Ext(name)
+	prefix := strings.TrimSuffix(name, ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var total int64
+	if info, err := os.Stat(c.filename); err == nil {
+		total = info.Size()
+	}
+	var backups []os.FileInfo
+	for _, e := range entries {
+		n := e.Name()
+		if e.IsDir() || !strings.HasPrefix(n, prefix) ||
+			!(strings.HasSuffix(n, ext) || strings.HasSuffix(n, ext+".gz") || strings.HasSuffix(n, ext+zstdSuffix)) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, info)
+		total += info.Size()
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Name() < backups[j].Name()
+	})
+	for _, b := range backups {
+		if total <= c.maxTotal {
+			return
+		}
+		if err := os.Remove(filepath.Join(dir, b.Name())); err == nil || os.IsNotExist(err) {
+			total -= b.Size()
+		}
+	}
+}
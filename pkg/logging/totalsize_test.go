@@ -0,0 +1,101 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	testutils "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging total size cap", func() {
+	var tmpDir string
+
+	// writeBackup creates a backup of multus.log of size megabytes
+	writeBackup := func(timestamp string, size int) string {
+		name := filepath.Join(tmpDir, "multus-"+timestamp+".log")
+		gomega.Expect(os.WriteFile(name, make([]byte, size*megabyte), 0644)).To(gomega.Succeed())
+		return name
+	}
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		tmpDir = GinkgoT().TempDir()
+		gomega.Expect(SetLogFile(filepath.Join(tmpDir, "multus.log"))).To(gomega.Succeed())
+		SetLogLevel("verbose")
+	})
+
+	AfterEach(func() {
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	It("Check the oldest backups are removed on rotation", func() {
+		// MaxAge and MaxBackups are disabled so that lumberjack keeps the
+		// backups
+		gomega.Expect(SetLogOptions(&LogOptions{
+			MaxAge:       testutils.Int(0),
+			MaxBackups:   testutils.Int(0),
+			Compress:     testutils.Bool(false),
+			MaxTotalSize: testutils.Int(2),
+		})).To(gomega.Succeed())
+		gomega.Expect(*GetLogOptions().MaxTotalSize).To(gomega.Equal(2))
+
+		oldest := writeBackup("2023-01-01T00-00-00.000", 1)
+		older := writeBackup("2023-01-02T00-00-00.000", 1)
+		old := writeBackup("2023-01-03T00-00-00.000", 1)
+		Verbosef("before rotation")
+		gomega.Expect(Rotate()).To(gomega.Succeed())
+
+		gomega.Expect(oldest).NotTo(gomega.BeAnExistingFile())
+		gomega.Expect(older).NotTo(gomega.BeAnExistingFile())
+		gomega.Expect(old).To(gomega.BeAnExistingFile())
+		backups, err := filepath.Glob(filepath.Join(tmpDir, "multus-*.log"))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(backups).To(gomega.HaveLen(2))
+	})
+
+	It("Check the backups are swept when a write rotates the log file", func() {
+		gomega.Expect(SetLogOptions(&LogOptions{
+			MaxAge:       testutils.Int(0),
+			MaxBackups:   testutils.Int(0),
+			Compress:     testutils.Bool(false),
+			MaxSize:      testutils.Int(1),
+			MaxTotalSize: testutils.Int(1),
+		})).To(gomega.Succeed())
+		old := writeBackup("2023-01-01T00-00-00.000", 1)
+
+		line := strings.Repeat("x", megabyte/2-100)
+		Verbosef("%s", line)
+		Verbosef("%s", line)
+		gomega.Expect(old).To(gomega.BeAnExistingFile())
+		// the third line does not fit in the log file, which is rotated
+		Verbosef("%s", line)
+		gomega.Expect(old).NotTo(gomega.BeAnExistingFile())
+	})
+
+	It("Check the total size is not capped by default", func() {
+		gomega.Expect(SetLogOptions(&LogOptions{MaxAge: testutils.Int(0), MaxBackups: testutils.Int(0)})).To(gomega.Succeed())
+		old := writeBackup("2023-01-01T00-00-00.000", 1)
+		gomega.Expect(Rotate()).To(gomega.Succeed())
+		gomega.Expect(old).To(gomega.BeAnExistingFile())
+		gomega.Expect(*GetLogOptions().MaxTotalSize).To(gomega.Equal(0))
+	})
+})
@@ -0,0 +1,65 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+// writeErrorHandling is set while a write error handler runs
+var writeErrorHandling atomic.Bool
+
+// writeErrorWarned is set once warnWriteError has written its warning
+var writeErrorWarned atomic.Bool
+
+// SetWriteErrorHandler sets the function called with the errors writing log
+// lines to the log file or to the error log file, e.g. when the disk is full,
+// so that operators can alert on them. The handler runs in its own goroutine
+// and may log: write errors occurring while it runs are not reported, so a
+// handler logging to the failing file does not loop. The default handler,
+// restored by passing nil, writes a single warning to stderr
+func SetWriteErrorHandler(fn func(error)) {
+	if fn == nil {
+		fn = warnWriteError
+	}
+
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	loggingWriteErrorHandler = fn
+}
+
+// warnWriteError is the default write error handler, it writes a warning to
+// stderr for the first write error only
+func warnWriteError(err error) {
+	if writeErrorWarned.CompareAndSwap(false, true) {
+		fmt.Fprintf(os.Stderr, "multus logging: %v, further write errors are not reported\n", err)
+	}
+}
+
+// reportWriteError calls the write error handler fn with err unless a handler
+// is already running. The line is written holding loggingMutex or by the
+// async writer, which the handler logging would wait for, so it is called in
+// another goroutine
+func reportWriteError(fn func(error), err error) {
+	if fn == nil || !writeErrorHandling.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer writeErrorHandling.Store(false)
+		fn(err)
+	}()
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging write errors", func() {
+
+	// handled records the errors passed to the write error handler
+	var mu sync.Mutex
+	var handled []error
+
+	handledErrors := func() []error {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]error(nil), handled...)
+	}
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		loggingW = failingWriter{}
+		loggingLevel.Store(DebugLevel)
+		handled = nil
+		SetWriteErrorHandler(func(err error) {
+			mu.Lock()
+			handled = append(handled, err)
+			mu.Unlock()
+		})
+	})
+
+	AfterEach(func() {
+		gomega.Eventually(writeErrorHandling.Load).Should(gomega.BeFalse())
+	})
+
+	It("Check the handler gets the log file write error", func() {
+		Debugf("foobar")
+		gomega.Eventually(handledErrors).Should(gomega.HaveLen(1))
+		gomega.Expect(handledErrors()[0]).To(gomega.MatchError("failed to write the log file: write failed"))
+
+		gomega.Eventually(writeErrorHandling.Load).Should(gomega.BeFalse())
+		loggingW = io.Discard
+		Debugf("foobar")
+		gomega.Consistently(handledErrors).Should(gomega.HaveLen(1))
+	})
+
+	It("Check a handler logging to the failing file does not loop", func() {
+		calls := 0
+		SetWriteErrorHandler(func(err error) {
+			mu.Lock()
+			calls++
+			mu.Unlock()
+			_ = Errorf("logging failed: %v", err)
+		})
+		Debugf("foobar")
+		gomega.Eventually(writeErrorHandling.Load).Should(gomega.BeFalse())
+		gomega.Consistently(func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			return calls
+		}).Should(gomega.Equal(1))
+	})
+
+	It("Check the default handler warns on stderr once", func() {
+		r, w, err := os.Pipe()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		defer r.Close()
+		stderr := os.Stderr
+		os.Stderr = w
+		defer func() { os.Stderr = stderr }()
+
+		SetWriteErrorHandler(nil)
+		Debugf("foo")
+		gomega.Eventually(writeErrorWarned.Load).Should(gomega.BeTrue())
+		gomega.Eventually(writeErrorHandling.Load).Should(gomega.BeFalse())
+		Debugf("bar")
+		gomega.Eventually(writeErrorHandling.Load).Should(gomega.BeFalse())
+
+		w.Close()
+		out, err := io.ReadAll(r)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(string(out)).To(gomega.Equal("multus logging: failed to write the log file: write failed, further write errors are not reported\n"))
+	})
+})
@@ -0,0 +1,72 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdSuffix is appended to the name of the backups compressed with zstd
+const zstdSuffix = ".zst"
+
+// Compression formats of the compressFormat log option
+const (
+	compressFormatGzip = "gzip"
+	compressFormatZstd = "zstd"
+)
+
+// compressZstd compresses the file with zstd into the file with zstdSuffix
+// appended, which gets the same mode, and removes it
+func compressZstd(filename string) (err error) {
+	src, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dstName := filename + zstdSuffix
+	dst, err := os.OpenFile(dstName, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			dst.Close()
+			os.Remove(dstName)
+		}
+	}()
+	enc, err := zstd.NewWriter(dst)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(enc, src); err != nil {
+		enc.Close()
+		return err
+	}
+	if err = enc.Close(); err != nil {
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(filename)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2023 Multus Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	testutils "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = Describe("logging zstd compression", func() {
+	var tmpDir string
+
+	glob := func(pattern string) func() []string {
+		return func() []string {
+			matches, err := filepath.Glob(filepath.Join(tmpDir, pattern))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			return matches
+		}
+	}
+
+	BeforeEach(func() {
+		Reset()
+		loggingStderr = false
+		tmpDir = GinkgoT().TempDir()
+		gomega.Expect(SetLogFile(filepath.Join(tmpDir, "multus.log"))).To(gomega.Succeed())
+		SetLogLevel("verbose")
+	})
+
+	AfterEach(func() {
+		gomega.Expect(Close()).To(gomega.Succeed())
+	})
+
+	It("Check the compress format is validated", func() {
+		format := "lz4"
+		gomega.Expect(SetLogOptions(&LogOptions{CompressFormat: &format})).To(gomega.MatchError(gomega.ContainSubstring("invalid log option compressFormat")))
+		gomega.Expect(GetLogOptions().CompressFormat).To(gomega.BeNil())
+	})
+
+	It("Check the backups are compressed with zstd", func() {
+		format := "zstd"
+		gomega.Expect(SetLogOptions(&LogOptions{
+			MaxBackups:     testutils.Int(2),
+			MaxAge:         testutils.Int(1),
+			CompressFormat: &format,
+		})).To(gomega.Succeed())
+		gomega.Expect(logger.Compress).To(gomega.BeFalse())
+		options := GetLogOptions()
+		gomega.Expect(*options.Compress).To(gomega.BeTrue())
+		gomega.Expect(*options.CompressFormat).To(gomega.Equal("zstd"))
+
+		old := filepath.Join(tmpDir, "multus-2000-01-01T00-00-00.000.log.zst")
+		gomega.Expect(os.WriteFile(old, nil, 0644)).To(gomega.Succeed())
+		for i := 0; i < 3; i++ {
+			Verbosef("line %d", i)
+			gomega.Expect(Rotate()).To(gomega.Succeed())
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		gomega.Eventually(glob("multus-*.log.zst")).Should(gomega.HaveLen(2))
+		gomega.Eventually(glob("multus-*.log")).Should(gomega.BeEmpty())
+		gomega.Expect(old).NotTo(gomega.BeAnExistingFile())
+
+		backups := glob("multus-*.log.zst")()
+		f, err := os.Open(backups[len(backups)-1])
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		defer f.Close()
+		dec, err := zstd.NewReader(f)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		defer dec.Close()
+		data, err := io.ReadAll(dec)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(string(data)).To(gomega.ContainSubstring("line 2"))
+	})
+})
@@ -125,10 +125,13 @@ func shimConfig(cniConfig []byte) (*ShimNetConf, error) {
 	// Logging
 	logging.SetLogStderr(multusConfig.LogToStderr)
 	if multusConfig.LogFile != "" {
-		logging.SetLogFile(multusConfig.LogFile)
+		if err := logging.SetLogFile(multusConfig.LogFile); err != nil {
+			_ = logging.Errorf("shimConfig: %v", err)
+		}
 	}
 	if multusConfig.LogLevel != "" {
-		logging.SetLogLevel(multusConfig.LogLevel)
+		// SetLogLevel reports an invalid level itself
+		_ = logging.SetLogLevel(multusConfig.LogLevel)
 	}
 	return multusConfig, nil
 }
@@ -670,11 +670,19 @@ func LoadDaemonNetConf(config []byte) (*ControllerNetConf, error) {
 	}
 
 	logging.SetLogStderr(daemonNetConf.LogToStderr)
+	var logFileErr error
 	if daemonNetConf.LogFile != DefaultMultusDaemonConfigFile {
-		logging.SetLogFile(daemonNetConf.LogFile)
+		if logFileErr = logging.SetLogFile(daemonNetConf.LogFile); logFileErr != nil {
+			logging.SetLogStderr(true)
+		}
 	}
 	if daemonNetConf.LogLevel != "" {
-		logging.SetLogLevel(daemonNetConf.LogLevel)
+		// SetLogLevel reports an invalid level itself
+		_ = logging.SetLogLevel(daemonNetConf.LogLevel)
+	}
+	// warned once the log level is set, so that it is not filtered out
+	if logFileErr != nil {
+		logging.Warningf("failed to set the log file of the daemon configuration, logging to stderr: %v", logFileErr)
 	}
 	daemonNetConf.ConfigFileContents = config
 
@@ -15,6 +15,10 @@
 package server
 
 import (
+	"path/filepath"
+
+	"gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/logging"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -49,6 +53,16 @@ var _ = Describe("Server", func() {
 	"socketDir": "/host/run/multus/socket"
 }`)
 
+	It("falls back to stderr when the log file of the daemon cannot be opened", func() {
+		defer logging.Reset()
+		logFile := filepath.Join(GinkgoT().TempDir(), "missing", "multus.log")
+		conf, err := LoadDaemonNetConf([]byte(`{"logFile": "` + logFile + `", "logToStderr": false, "logLevel": "verbose"}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conf.LogFile).To(Equal(logFile))
+		Expect(logging.GetLogFile()).To(BeEmpty())
+		Expect(logging.GetLogStderr()).To(BeTrue())
+	})
+
 	Context("correctly overrides incoming CNI config with server config", func() {
 		newConf, err := overrideCNIConfigWithServerConfig(cniConf, serverConf, false)
 		Expect(err).ToNot(HaveOccurred())
@@ -348,13 +348,8 @@ func LoadNetConf(bytes []byte) (*NetConf, error) {
 	}
 
 	// Logging
-	logging.SetLogStderr(netconf.LogToStderr)
-	logging.SetLogOptions(netconf.LogOptions)
-	if netconf.LogFile != "" {
-		logging.SetLogFile(netconf.LogFile)
-	}
-	if netconf.LogLevel != "" {
-		logging.SetLogLevel(netconf.LogLevel)
+	if err := logging.ConfigureFromNetConf(netconf.LogLevel, netconf.LogFile, netconf.LogToStderr, netconf.LogOptions); err != nil {
+		logging.Warningf("LoadNetConf: %v", err)
 	}
 
 	// Parse previous result